@@ -16,18 +16,22 @@ const (
 	FORBIDDEN_NO_TENANT_CODE   ErrorCode = "FORBIDDEN_NO_TENANT"
 	CSRF_TOKEN_MISMATCH_CODE   ErrorCode = "CSRF_MISMATCH"
 	RECORD_NOT_FOUND_CODE      ErrorCode = "RECORD_NOT_FOUND"
+	TOO_MANY_REQUESTS_CODE     ErrorCode = "TOO_MANY_REQUESTS"
 	INTERNAL_SERVER_ERROR_CODE ErrorCode = "INTERNAL_SERVER_ERROR"
+	SERVICE_UNAVAILABLE_CODE   ErrorCode = "SERVICE_UNAVAILABLE"
 )
 
 var (
-	Err400InvalidAction     = errors.New("invalid action")
-	Err400InvalidBody       = errors.New("invalid body")
-	Err400InvalidData       = errors.New("invalid data")
-	Err400InvalidParams     = errors.New("invalid params")
-	Err401Unauthorized      = errors.New("unauthorized")
-	Err403Forbidden         = errors.New("forbidden")
-	Err403NoTenant          = errors.New("user has no tenant")
-	Err403CSRFTokenMismatch = errors.New("csrf token mismatch")
-	Err404RecordNotFound    = errors.New("record not found")
-	Err500InternalServer    = errors.New("internal server error")
+	Err400InvalidAction      = errors.New("invalid action")
+	Err400InvalidBody        = errors.New("invalid body")
+	Err400InvalidData        = errors.New("invalid data")
+	Err400InvalidParams      = errors.New("invalid params")
+	Err401Unauthorized       = errors.New("unauthorized")
+	Err403Forbidden          = errors.New("forbidden")
+	Err403NoTenant           = errors.New("user has no tenant")
+	Err403CSRFTokenMismatch  = errors.New("csrf token mismatch")
+	Err404RecordNotFound     = errors.New("record not found")
+	Err429TooManyRequests    = errors.New("too many requests")
+	Err500InternalServer     = errors.New("internal server error")
+	Err503ServiceUnavailable = errors.New("service unavailable")
 )
@@ -1,8 +1,8 @@
 package asyncutil
 
 import (
-	"fmt"
-	"runtime/debug"
+	"context"
+	"log/slog"
 )
 
 // Result represents the result of an asynchronous computation.
@@ -13,6 +13,7 @@ type Result[T any] struct {
 
 // OnPanic is a global handler that will be called whenever a panic is recovered.
 // You can assign this to send error to Sentry, log, metrics, etc.
+// The error is a *PanicError; see OnPanicCtx for a handler that also gets the task context.
 var OnPanic func(err error)
 
 // SafeGo runs a function asynchronously and recovers from panics.
@@ -21,28 +22,86 @@ func SafeGo[T any](fn func() (T, error)) <-chan Result[T] {
 	ch := make(chan Result[T], 1)
 
 	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				var zero T
-				err := fmt.Errorf("panic recovered: %v\n%s", r, debug.Stack())
-
-				if OnPanic != nil {
-					// protect OnPanic from panicking
-					defer func() {
-						if rec := recover(); rec != nil {
-							fmt.Printf("panic in OnPanic: %v\n", rec)
-						}
-					}()
-					OnPanic(err)
-				}
-
-				ch <- Result[T]{Value: zero, Err: err}
-			}
-		}()
-
-		val, err := fn()
+		val, err := runTask(context.Background(), fn)
 		ch <- Result[T]{Value: val, Err: err}
 	}()
 
 	return ch
 }
+
+// SafeGoCtx runs a context-aware function asynchronously and recovers from panics.
+// The context is passed to fn so it can stop work when the caller cancels or the
+// deadline passes. If ctx is already done, fn is not started and the result holds ctx.Err().
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+//	defer cancel()
+//
+//	ch := SafeGoCtx(ctx, func(ctx context.Context) (User, error) {
+//	    return repo.FindUser(ctx, id)
+//	})
+//
+//	user, err := Await(ctx, ch)
+func SafeGoCtx[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) <-chan Result[T] {
+	ch := make(chan Result[T], 1)
+
+	if err := ctx.Err(); err != nil {
+		var zero T
+		ch <- Result[T]{Value: zero, Err: err}
+		return ch
+	}
+
+	go func() {
+		val, err := runTask(ctx, func() (T, error) { return fn(ctx) })
+		ch <- Result[T]{Value: val, Err: err}
+	}()
+
+	return ch
+}
+
+// Await waits for the result of ch. It returns ctx.Err() if the context is
+// cancelled or its deadline passes before the result arrives.
+//
+// The goroutine behind ch keeps running after Await gives up; use SafeGoCtx with
+// the same context so it can stop as well.
+func Await[T any](ctx context.Context, ch <-chan Result[T]) (T, error) {
+	select {
+	case res := <-ch:
+		return res.Value, res.Err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// safeCall runs fn and converts a panic into a *PanicError, reporting it to
+// OnPanic and OnPanicCtx.
+func safeCall[T any](ctx context.Context, fn func() (T, error)) (val T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			perr := newPanicError(ctx, r)
+			val, err = zero, perr
+			notifyPanic(ctx, perr)
+		}
+	}()
+
+	return fn()
+}
+
+// notifyPanic calls the panic handlers, protecting the caller from a panicking handler.
+func notifyPanic(ctx context.Context, err *PanicError) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			slog.ErrorContext(ctx, "asyncutil: panic in panic handler", slog.Any("panic", rec), slog.Any("err", err))
+		}
+	}()
+
+	if OnPanic != nil {
+		OnPanic(err)
+	}
+	if OnPanicCtx != nil {
+		OnPanicCtx(ctx, err)
+	}
+}
@@ -1,9 +1,11 @@
 package asyncutil
 
 import (
+	"context"
 	"errors"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -190,6 +192,132 @@ func TestAsyncUtil_SafeGo_WithOnPanic(t *testing.T) {
 	}
 }
 
+func TestAsyncUtil_SafeGoCtx(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         func() (context.Context, context.CancelFunc)
+		fn          func(ctx context.Context) (int, error)
+		expected    int
+		expectedErr error
+		errContains string
+	}{
+		{
+			name: "success",
+			ctx:  func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			fn: func(ctx context.Context) (int, error) {
+				return 42, nil
+			},
+			expected: 42,
+		},
+		{
+			name: "fn observes cancellation",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+			fn: func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+				return 0, ctx.Err()
+			},
+			expectedErr: context.DeadlineExceeded,
+		},
+		{
+			name: "already cancelled context does not start fn",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			fn: func(ctx context.Context) (int, error) {
+				panic("should not run")
+			},
+			expectedErr: context.Canceled,
+		},
+		{
+			name: "panic",
+			ctx:  func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			fn: func(ctx context.Context) (int, error) {
+				panic("boom!")
+			},
+			errContains: "panic recovered: boom!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			res := <-SafeGoCtx(ctx, tt.fn)
+
+			assert.Equal(t, tt.expected, res.Value)
+			switch {
+			case tt.expectedErr != nil:
+				assert.ErrorIs(t, res.Err, tt.expectedErr)
+			case tt.errContains != "":
+				assert.ErrorContains(t, res.Err, tt.errContains)
+			default:
+				assert.NoError(t, res.Err)
+			}
+		})
+	}
+}
+
+func TestAsyncUtil_SafeGoCtx_WithOnPanic(t *testing.T) {
+	var capturedErr atomic.Value
+	OnPanic = func(err error) {
+		capturedErr.Store(err)
+	}
+	defer func() { OnPanic = nil }()
+
+	res := <-SafeGoCtx(context.Background(), func(ctx context.Context) (string, error) {
+		panic("ctx boom")
+	})
+
+	assert.ErrorContains(t, res.Err, "panic recovered: ctx boom")
+	assert.ErrorContains(t, capturedErr.Load().(error), "panic recovered: ctx boom")
+}
+
+func TestAsyncUtil_Await(t *testing.T) {
+	t.Run("returns result", func(t *testing.T) {
+		ch := SafeGo(func() (string, error) {
+			return "done", nil
+		})
+
+		val, err := Await(context.Background(), ch)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "done", val)
+	})
+
+	t.Run("returns error result", func(t *testing.T) {
+		ch := SafeGo(func() (string, error) {
+			return "", errors.New("failed")
+		})
+
+		_, err := Await(context.Background(), ch)
+
+		assert.EqualError(t, err, "failed")
+	})
+
+	t.Run("returns ctx error on timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		ch := SafeGo(func() (string, error) {
+			<-release
+			return "late", nil
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		val, err := Await(ctx, ch)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Empty(t, val)
+	})
+}
+
 func BenchmarkAsyncUtil_SafeGo(b *testing.B) {
 	for b.Loop() {
 		ch := SafeGo(func() (int, error) {
@@ -198,3 +326,13 @@ func BenchmarkAsyncUtil_SafeGo(b *testing.B) {
 		<-ch
 	}
 }
+
+func BenchmarkAsyncUtil_SafeGoCtx(b *testing.B) {
+	ctx := context.Background()
+
+	for b.Loop() {
+		Await(ctx, SafeGoCtx(ctx, func(ctx context.Context) (int, error) {
+			return 42, nil
+		}))
+	}
+}
@@ -0,0 +1,254 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBatcherClosed is returned when adding to a Batcher that is closing.
+var ErrBatcherClosed = errors.New("asyncutil: batcher is closed")
+
+// BatcherConfig configures a Batcher.
+type BatcherConfig struct {
+	// Size flushes the buffer as soon as it holds this many items. Defaults to 100.
+	Size int
+
+	// Interval flushes a non-empty buffer periodically, so items never wait much
+	// longer than Interval. Defaults to 1 second.
+	Interval time.Duration
+
+	// OnError is called when a flush triggered by Size or Interval fails.
+	// Errors of Flush and Close are returned to their caller instead.
+	OnError func(err error)
+
+	// Clock drives Interval. Defaults to RealClock.
+	Clock Clock
+}
+
+// Batcher buffers items and hands them to a flush callback in batches, by size
+// or by interval. Batches are flushed one at a time, in order. The callback is
+// protected the same way as SafeGo: panics are recovered, reported to OnPanic
+// and treated as a flush error.
+//
+// The callback gets the ctx of Flush and Close for the batches they flush.
+// Size and interval batches get a context that is cancelled when the ctx of
+// the first Close is done, so a shutdown deadline also stops a slow flush.
+//
+// Example:
+//
+//	audit := NewBatcher(BatcherConfig{Size: 500, Interval: 2 * time.Second}, func(ctx context.Context, events []AuditEvent) error {
+//	    return repo.InsertAuditEvents(ctx, events)
+//	})
+//
+//	audit.Add(AuditEvent{Action: "user.login", UserID: id})
+//
+//	// On shutdown
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	if err := audit.Close(ctx); err != nil {
+//	    slog.Error("audit events lost", "err", err)
+//	}
+type Batcher[T any] struct {
+	cfg   BatcherConfig
+	flush func(ctx context.Context, items []T) error
+	clock Clock
+
+	mu      sync.Mutex
+	buf     []T
+	closed  bool
+	sending sync.WaitGroup
+
+	ctx      context.Context // passed to size and interval flushes
+	cancel   context.CancelFunc
+	batches  chan batchRequest[T]
+	quit     chan struct{}
+	done     chan struct{}
+	closeErr error
+}
+
+type batchRequest[T any] struct {
+	ctx   context.Context
+	items []T
+	drain bool       // flush the buffer as it is when the loop gets the request
+	done  chan error // nil for size-triggered batches
+}
+
+// NewBatcher starts a Batcher that passes batches to flush.
+func NewBatcher[T any](cfg BatcherConfig, flush func(ctx context.Context, items []T) error) *Batcher[T] {
+	if cfg.Size <= 0 {
+		cfg.Size = 100
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	b := &Batcher[T]{
+		cfg:     cfg,
+		flush:   flush,
+		clock:   clockOrReal(cfg.Clock),
+		ctx:     ctx,
+		cancel:  cancel,
+		batches: make(chan batchRequest[T]),
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go b.loop()
+
+	return b
+}
+
+// Add buffers item. When the buffer reaches Size, Add blocks until the flush
+// loop accepts the batch, which gives natural backpressure.
+func (b *Batcher[T]) Add(item T) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrBatcherClosed
+	}
+
+	b.buf = append(b.buf, item)
+	if len(b.buf) < b.cfg.Size {
+		b.mu.Unlock()
+		return nil
+	}
+
+	items := b.take()
+	b.sending.Add(1)
+	b.mu.Unlock()
+
+	defer b.sending.Done()
+	b.batches <- batchRequest[T]{ctx: b.ctx, items: items}
+
+	return nil
+}
+
+// Flush hands the buffered items and ctx to the flush callback and waits until
+// they, and every batch queued before them, have been flushed. It returns the
+// error of the callback, or ctx.Err() if the context is done first.
+func (b *Batcher[T]) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	closed := b.closed
+	b.mu.Unlock()
+
+	if closed {
+		return ErrBatcherClosed
+	}
+
+	req := batchRequest[T]{ctx: ctx, drain: true, done: make(chan error, 1)}
+
+	select {
+	case b.batches <- req:
+	case <-b.done:
+		return ErrBatcherClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-req.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting items, flushes what is buffered and stops the flush
+// loop. It waits until that is done or ctx is done, and returns the error of
+// the final flush or ctx.Err(). The ctx of the first Close is passed to the
+// final flush, and cancels the batches still in flight once it is done.
+// Calling Close again waits the same way.
+func (b *Batcher[T]) Close(ctx context.Context) error {
+	b.mu.Lock()
+	first := !b.closed
+	b.closed = true
+	b.mu.Unlock()
+
+	if first {
+		stop := context.AfterFunc(ctx, b.cancel)
+
+		go func() {
+			defer stop()
+
+			// Let Add calls that filled a batch before Close hand it over first.
+			b.sending.Wait()
+
+			req := batchRequest[T]{ctx: ctx, drain: true, done: make(chan error, 1)}
+			b.batches <- req
+			b.closeErr = <-req.done
+
+			close(b.quit)
+		}()
+	}
+
+	select {
+	case <-b.done:
+		return b.closeErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// loop flushes batches one at a time until Close.
+func (b *Batcher[T]) loop() {
+	defer close(b.done)
+	defer b.cancel()
+
+	tick := b.clock.After(b.cfg.Interval)
+
+	for {
+		select {
+		case req := <-b.batches:
+			items := req.items
+			if req.drain {
+				b.mu.Lock()
+				items = b.take()
+				b.mu.Unlock()
+			}
+
+			err := b.process(req.ctx, items)
+			if req.done != nil {
+				req.done <- err
+			} else if err != nil && b.cfg.OnError != nil {
+				b.cfg.OnError(err)
+			}
+
+		case <-tick:
+			tick = b.clock.After(b.cfg.Interval)
+
+			b.mu.Lock()
+			items := b.take()
+			b.mu.Unlock()
+
+			if err := b.process(b.ctx, items); err != nil && b.cfg.OnError != nil {
+				b.cfg.OnError(err)
+			}
+
+		case <-b.quit:
+			return
+		}
+	}
+}
+
+func (b *Batcher[T]) process(ctx context.Context, items []T) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	_, err := safeCall(ctx, func() (struct{}, error) {
+		return struct{}{}, b.flush(ctx, items)
+	})
+
+	return err
+}
+
+// take returns the buffered items and starts a new buffer. b.mu must be held.
+func (b *Batcher[T]) take() []T {
+	items := b.buf
+	b.buf = nil
+	return items
+}
@@ -0,0 +1,260 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchRecorder collects the batches passed to a Batcher flush callback.
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (r *batchRecorder) flush(ctx context.Context, items []int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.batches = append(r.batches, items)
+	return r.err
+}
+
+func (r *batchRecorder) get() [][]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.batches
+}
+
+func TestAsyncUtil_Batcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      BatcherConfig
+		items    []int
+		advance  time.Duration
+		expected [][]int
+	}{
+		{
+			name:     "flushes on size",
+			cfg:      BatcherConfig{Size: 2, Interval: time.Hour},
+			items:    []int{1, 2, 3, 4, 5},
+			expected: [][]int{{1, 2}, {3, 4}},
+		},
+		{
+			name:     "flushes on interval",
+			cfg:      BatcherConfig{Size: 10, Interval: time.Second},
+			items:    []int{1, 2, 3},
+			advance:  time.Second,
+			expected: [][]int{{1, 2, 3}},
+		},
+		{
+			name:     "interval does not flush an empty buffer",
+			cfg:      BatcherConfig{Size: 2, Interval: time.Second},
+			items:    []int{1, 2},
+			advance:  time.Second,
+			expected: [][]int{{1, 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+			tt.cfg.Clock = clock
+
+			rec := &batchRecorder{}
+			b := NewBatcher(tt.cfg, rec.flush)
+
+			for _, item := range tt.items {
+				require.NoError(t, b.Add(item))
+			}
+
+			if tt.advance > 0 {
+				waitForWaiters(t, clock, 1)
+				clock.Advance(tt.advance)
+			}
+
+			assert.Eventually(t, func() bool {
+				return assert.ObjectsAreEqual(tt.expected, rec.get())
+			}, time.Second, time.Millisecond)
+		})
+	}
+}
+
+func TestAsyncUtil_Batcher_FlushAndClose(t *testing.T) {
+	rec := &batchRecorder{}
+	b := NewBatcher(BatcherConfig{Size: 3, Interval: time.Hour}, rec.flush)
+
+	b.Add(1)
+	b.Add(2)
+	require.NoError(t, b.Flush(context.Background()))
+	assert.Equal(t, [][]int{{1, 2}}, rec.get())
+
+	// Flushing an empty buffer is a no-op.
+	require.NoError(t, b.Flush(context.Background()))
+	assert.Len(t, rec.get(), 1)
+
+	b.Add(3)
+	require.NoError(t, b.Close(context.Background()))
+	assert.Equal(t, [][]int{{1, 2}, {3}}, rec.get())
+
+	assert.ErrorIs(t, b.Add(4), ErrBatcherClosed)
+	assert.ErrorIs(t, b.Flush(context.Background()), ErrBatcherClosed)
+	assert.NoError(t, b.Close(context.Background()), "closing twice waits again")
+}
+
+func TestAsyncUtil_Batcher_Errors(t *testing.T) {
+	errInsert := errors.New("insert failed")
+
+	t.Run("flush returns callback error", func(t *testing.T) {
+		rec := &batchRecorder{err: errInsert}
+		b := NewBatcher(BatcherConfig{Size: 10}, rec.flush)
+		defer b.Close(context.Background())
+
+		b.Add(1)
+		assert.ErrorIs(t, b.Flush(context.Background()), errInsert)
+	})
+
+	t.Run("size-triggered errors go to OnError", func(t *testing.T) {
+		errs := make(chan error, 1)
+		rec := &batchRecorder{err: errInsert}
+		b := NewBatcher(BatcherConfig{Size: 1, OnError: func(err error) { errs <- err }}, rec.flush)
+		defer b.Close(context.Background())
+
+		b.Add(1)
+		assert.ErrorIs(t, <-errs, errInsert)
+	})
+
+	t.Run("panic in callback", func(t *testing.T) {
+		b := NewBatcher(BatcherConfig{Size: 10}, func(ctx context.Context, items []int) error {
+			panic("batch boom")
+		})
+
+		b.Add(1)
+		err := b.Close(context.Background())
+
+		var perr *PanicError
+		assert.ErrorAs(t, err, &perr)
+		assert.Equal(t, "batch boom", perr.Value)
+	})
+
+	t.Run("close gives up when context is done", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		b := NewBatcher(BatcherConfig{Size: 10}, func(ctx context.Context, items []int) error {
+			<-release
+			return nil
+		})
+		b.Add(1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, b.Close(ctx), context.DeadlineExceeded)
+	})
+}
+
+func TestAsyncUtil_Batcher_Context(t *testing.T) {
+	type ctxKey struct{}
+
+	t.Run("flush passes the caller context", func(t *testing.T) {
+		got := make(chan any, 1)
+		b := NewBatcher(BatcherConfig{Size: 10}, func(ctx context.Context, items []int) error {
+			got <- ctx.Value(ctxKey{})
+			return nil
+		})
+		defer b.Close(context.Background())
+
+		b.Add(1)
+		require.NoError(t, b.Flush(context.WithValue(context.Background(), ctxKey{}, "request")))
+		assert.Equal(t, "request", <-got)
+	})
+
+	t.Run("close deadline cancels the final flush", func(t *testing.T) {
+		b := NewBatcher(BatcherConfig{Size: 10}, func(ctx context.Context, items []int) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		b.Add(1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, b.Close(ctx), context.DeadlineExceeded)
+		assert.ErrorIs(t, b.Close(context.Background()), context.DeadlineExceeded, "the final flush saw the deadline")
+	})
+
+	t.Run("close deadline cancels an interval flush", func(t *testing.T) {
+		clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		started := make(chan struct{})
+		cancelled := make(chan error, 1)
+
+		b := NewBatcher(BatcherConfig{Size: 10, Interval: time.Second, Clock: clock}, func(ctx context.Context, items []int) error {
+			if items[0] == 1 {
+				close(started)
+				<-ctx.Done()
+				cancelled <- ctx.Err()
+			}
+			return nil
+		})
+		b.Add(1)
+
+		waitForWaiters(t, clock, 1)
+		clock.Advance(time.Second)
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, b.Close(ctx), context.DeadlineExceeded)
+		assert.ErrorIs(t, <-cancelled, context.Canceled)
+	})
+}
+
+func TestAsyncUtil_Batcher_ConcurrentAdd(t *testing.T) {
+	rec := &batchRecorder{}
+	b := NewBatcher(BatcherConfig{Size: 7, Interval: time.Hour}, rec.flush)
+
+	var wg sync.WaitGroup
+	for g := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				b.Add(g*100 + i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.NoError(t, b.Close(context.Background()))
+
+	seen := make(map[int]bool)
+	for _, batch := range rec.get() {
+		assert.LessOrEqual(t, len(batch), 7)
+		for _, item := range batch {
+			seen[item] = true
+		}
+	}
+	assert.Len(t, seen, 1000)
+}
+
+func BenchmarkAsyncUtil_Batcher_Add(b *testing.B) {
+	batcher := NewBatcher(BatcherConfig{Size: 100}, func(ctx context.Context, items []int) error {
+		return nil
+	})
+	defer batcher.Close(context.Background())
+
+	i := 0
+	for b.Loop() {
+		batcher.Add(i)
+		i++
+	}
+}
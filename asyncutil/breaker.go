@@ -0,0 +1,437 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shoraid/stx-go-utils/apperror"
+)
+
+// ErrCircuitOpen is returned when a Breaker rejects a call. It wraps
+// apperror.Err503ServiceUnavailable, so httpresponse.HandleError answers 503.
+var ErrCircuitOpen = fmt.Errorf("asyncutil: circuit breaker is open: %w", apperror.Err503ServiceUnavailable)
+
+// BreakerState is the state of a Breaker.
+type BreakerState int
+
+const (
+	// StateClosed lets every call through and counts failures.
+	StateClosed BreakerState = iota
+
+	// StateOpen rejects every call with ErrCircuitOpen until the cooldown passes.
+	StateOpen
+
+	// StateHalfOpen lets a limited number of probe calls through to decide
+	// whether to close again or go back to open.
+	StateHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("BreakerState(%d)", int(s))
+	}
+}
+
+// BreakerConfig configures a Breaker. When neither ConsecutiveFailures nor
+// FailureRate is set, the breaker trips after 5 consecutive failures.
+type BreakerConfig struct {
+	// Name identifies the breaker in OnStateChange, e.g. the dependency it protects.
+	Name string
+
+	// ConsecutiveFailures trips the breaker after this many failures in a row.
+	// 0 disables the check.
+	ConsecutiveFailures int
+
+	// FailureRate trips the breaker when the share of failed calls in the rolling
+	// window reaches this value, between 0 and 1. 0 disables the check.
+	FailureRate float64
+
+	// MinRequests is the number of calls the window must hold before FailureRate
+	// is checked. Defaults to 10.
+	MinRequests int
+
+	// Window is the length of the rolling window used by FailureRate. Defaults to 1 minute.
+	Window time.Duration
+
+	// Buckets is the number of slices the window is split into. Older slices
+	// expire one at a time. Defaults to 10.
+	Buckets int
+
+	// Cooldown is how long the breaker stays open before letting probes through.
+	// Defaults to 30 seconds.
+	Cooldown time.Duration
+
+	// HalfOpenMaxCalls is the number of probes allowed while half-open. The breaker
+	// closes once they all succeed and opens again on the first failure. Defaults to 1.
+	HalfOpenMaxCalls int
+
+	// IsFailure decides whether a call result counts against the dependency.
+	// By default nil errors and the apperror 4xx sentinels are not failures.
+	// Calls cancelled with context.Canceled are never counted either way: they
+	// change no counters and free their half-open probe slot.
+	IsFailure func(err error) bool
+
+	// OnStateChange is called after every state transition, outside the breaker lock.
+	OnStateChange func(name string, from, to BreakerState)
+
+	// Clock is used to measure the window and cooldown. Defaults to RealClock.
+	Clock Clock
+}
+
+// Breaker is a circuit breaker. It stops calling a failing dependency for a
+// cooldown period, then lets a few probe calls through to check if it recovered.
+//
+// Example:
+//
+//	payments := NewBreaker(BreakerConfig{
+//	    Name:        "payments",
+//	    FailureRate: 0.5,
+//	    Cooldown:    10 * time.Second,
+//	    OnStateChange: func(name string, from, to BreakerState) {
+//	        slog.Warn("circuit breaker changed state", "name", name, "from", from, "to", to)
+//	    },
+//	})
+//
+//	ch := SafeGo(WithBreaker(payments, func() (Receipt, error) {
+//	    return paymentClient.Charge(req)
+//	}))
+//
+//	res := <-ch
+//	if httpresponse.HandleError(w, res.Err) { // 503 while the breaker is open
+//	    return
+//	}
+type Breaker struct {
+	cfg   BreakerConfig
+	clock Clock
+
+	mu             sync.Mutex
+	state          BreakerState
+	generation     uint64
+	openedAt       time.Time
+	consecutive    int
+	window         rollingWindow
+	probes         int
+	probeSuccesses int
+	pending        []stateChange
+}
+
+type stateChange struct {
+	from, to BreakerState
+}
+
+// NewBreaker returns a closed Breaker configured by cfg.
+func NewBreaker(cfg BreakerConfig) *Breaker {
+	if cfg.ConsecutiveFailures <= 0 && cfg.FailureRate <= 0 {
+		cfg.ConsecutiveFailures = 5
+	}
+	if cfg.MinRequests <= 0 {
+		cfg.MinRequests = 10
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = time.Minute
+	}
+	if cfg.Buckets <= 0 {
+		cfg.Buckets = 10
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 30 * time.Second
+	}
+	if cfg.HalfOpenMaxCalls <= 0 {
+		cfg.HalfOpenMaxCalls = 1
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = isBreakerFailure
+	}
+
+	return &Breaker{
+		cfg:    cfg,
+		clock:  clockOrReal(cfg.Clock),
+		window: newRollingWindow(cfg.Window, cfg.Buckets),
+	}
+}
+
+// State returns the current state, moving from open to half-open once the
+// cooldown has passed.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.refresh(b.clock.Now())
+	return b.state
+}
+
+// Reset closes the breaker and clears its counters.
+func (b *Breaker) Reset() {
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.setState(StateClosed, b.clock.Now())
+}
+
+// Allow asks the breaker for permission to make a call. It returns
+// ErrCircuitOpen if the call must not be made. Otherwise the caller must pass
+// the call result to done; calling done more than once has no effect.
+//
+// Prefer WithBreaker or WithBreakerCtx, which do this for you.
+//
+// Example:
+//
+//	done, err := breaker.Allow()
+//	if err != nil {
+//	    return err
+//	}
+//	err = client.Ping(ctx)
+//	done(err)
+func (b *Breaker) Allow() (done func(err error), err error) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.refresh(b.clock.Now())
+
+	switch b.state {
+	case StateOpen:
+		return nil, ErrCircuitOpen
+	case StateHalfOpen:
+		if b.probes >= b.cfg.HalfOpenMaxCalls {
+			return nil, ErrCircuitOpen
+		}
+		b.probes++
+	}
+
+	generation := b.generation
+	var once sync.Once
+
+	return func(err error) {
+		once.Do(func() { b.record(generation, err) })
+	}, nil
+}
+
+// WithBreaker wraps fn so that every call goes through b. The result can be
+// passed to SafeGo. Panics in fn are recovered, counted as failures and
+// returned as errors.
+//
+// Example:
+//
+//	fetch := WithBreaker(inventory, func() (Stock, error) {
+//	    return inventoryClient.Stock(sku)
+//	})
+//
+//	stock, err := Await(ctx, SafeGo(fetch))
+func WithBreaker[T any](b *Breaker, fn func() (T, error)) func() (T, error) {
+	return func() (T, error) {
+		return callWithBreaker(context.Background(), b, fn)
+	}
+}
+
+// WithBreakerCtx is the context-aware form of WithBreaker, for SafeGoCtx,
+// Retry and Group.
+//
+// Example:
+//
+//	user, err := Retry(ctx, RetryPolicy{}, WithBreakerCtx(users, func(ctx context.Context) (User, error) {
+//	    return userClient.Get(ctx, id)
+//	}))
+func WithBreakerCtx[T any](b *Breaker, fn func(ctx context.Context) (T, error)) func(ctx context.Context) (T, error) {
+	return func(ctx context.Context) (T, error) {
+		return callWithBreaker(ctx, b, func() (T, error) { return fn(ctx) })
+	}
+}
+
+func callWithBreaker[T any](ctx context.Context, b *Breaker, fn func() (T, error)) (T, error) {
+	done, err := b.Allow()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	val, err := safeCall(ctx, fn)
+	done(err)
+
+	return val, err
+}
+
+// record applies a call result. Results from calls allowed before the last
+// state change are ignored, and cancelled calls only release their probe.
+func (b *Breaker) record(generation uint64, err error) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	now := b.clock.Now()
+	b.refresh(now)
+
+	if generation != b.generation {
+		return
+	}
+
+	if errors.Is(err, context.Canceled) {
+		if b.state == StateHalfOpen {
+			b.probes--
+		}
+		return
+	}
+
+	failure := b.cfg.IsFailure(err)
+
+	switch b.state {
+	case StateClosed:
+		b.window.add(now, failure)
+		if failure {
+			b.consecutive++
+		} else {
+			b.consecutive = 0
+		}
+
+		if b.shouldTrip(now) {
+			b.setState(StateOpen, now)
+		}
+
+	case StateHalfOpen:
+		if failure {
+			b.setState(StateOpen, now)
+			return
+		}
+
+		b.probeSuccesses++
+		if b.probeSuccesses >= b.cfg.HalfOpenMaxCalls {
+			b.setState(StateClosed, now)
+		}
+	}
+}
+
+func (b *Breaker) shouldTrip(now time.Time) bool {
+	if b.cfg.ConsecutiveFailures > 0 && b.consecutive >= b.cfg.ConsecutiveFailures {
+		return true
+	}
+
+	if b.cfg.FailureRate > 0 {
+		successes, failures := b.window.totals(now)
+		total := successes + failures
+		if total >= b.cfg.MinRequests && float64(failures)/float64(total) >= b.cfg.FailureRate {
+			return true
+		}
+	}
+
+	return false
+}
+
+// refresh moves an open breaker to half-open once the cooldown has passed.
+func (b *Breaker) refresh(now time.Time) {
+	if b.state == StateOpen && now.Sub(b.openedAt) >= b.cfg.Cooldown {
+		b.setState(StateHalfOpen, now)
+	}
+}
+
+// setState switches to a new state and clears the counters. Callbacks are
+// queued and run by unlock.
+func (b *Breaker) setState(to BreakerState, now time.Time) {
+	from := b.state
+
+	b.state = to
+	b.generation++
+	b.consecutive = 0
+	b.probes = 0
+	b.probeSuccesses = 0
+	b.window.reset()
+
+	if to == StateOpen {
+		b.openedAt = now
+	}
+
+	if from != to {
+		b.pending = append(b.pending, stateChange{from: from, to: to})
+	}
+}
+
+// unlock releases the lock and then reports queued state changes, so
+// OnStateChange may call back into the breaker.
+func (b *Breaker) unlock() {
+	changes := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	if b.cfg.OnStateChange == nil {
+		return
+	}
+	for _, c := range changes {
+		b.cfg.OnStateChange(b.cfg.Name, c.from, c.to)
+	}
+}
+
+// isBreakerFailure is the default BreakerConfig.IsFailure.
+func isBreakerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, target := range nonRetryableErrors {
+		if errors.Is(err, target) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rollingWindow counts call results over a sliding period split into buckets.
+type rollingWindow struct {
+	width   time.Duration
+	buckets []windowBucket
+}
+
+type windowBucket struct {
+	epoch     int64
+	successes int
+	failures  int
+}
+
+func newRollingWindow(window time.Duration, buckets int) rollingWindow {
+	width := max(window/time.Duration(buckets), time.Nanosecond)
+	return rollingWindow{width: width, buckets: make([]windowBucket, buckets)}
+}
+
+func (w *rollingWindow) epoch(now time.Time) int64 {
+	return now.UnixNano() / int64(w.width)
+}
+
+func (w *rollingWindow) add(now time.Time, failure bool) {
+	epoch := w.epoch(now)
+	n := int64(len(w.buckets))
+	bucket := &w.buckets[(epoch%n+n)%n]
+
+	if bucket.epoch != epoch {
+		*bucket = windowBucket{epoch: epoch}
+	}
+
+	if failure {
+		bucket.failures++
+	} else {
+		bucket.successes++
+	}
+}
+
+func (w *rollingWindow) totals(now time.Time) (successes, failures int) {
+	oldest := w.epoch(now) - int64(len(w.buckets)) + 1
+
+	for _, bucket := range w.buckets {
+		if bucket.epoch >= oldest {
+			successes += bucket.successes
+			failures += bucket.failures
+		}
+	}
+
+	return successes, failures
+}
+
+func (w *rollingWindow) reset() {
+	clear(w.buckets)
+}
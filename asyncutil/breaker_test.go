@@ -0,0 +1,299 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shoraid/stx-go-utils/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBreakerClock() *FakeClock {
+	return NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func callBreaker(b *Breaker, err error) error {
+	_, callErr := WithBreaker(b, func() (int, error) { return 0, err })()
+	return callErr
+}
+
+func TestAsyncUtil_Breaker_Trips(t *testing.T) {
+	errDown := errors.New("dependency down")
+
+	tests := []struct {
+		name     string
+		cfg      BreakerConfig
+		results  []error
+		expected BreakerState
+	}{
+		{
+			name:     "default trips after 5 consecutive failures",
+			cfg:      BreakerConfig{},
+			results:  []error{errDown, errDown, errDown, errDown, errDown},
+			expected: StateOpen,
+		},
+		{
+			name:     "success resets consecutive failures",
+			cfg:      BreakerConfig{ConsecutiveFailures: 3},
+			results:  []error{errDown, errDown, nil, errDown, errDown},
+			expected: StateClosed,
+		},
+		{
+			name:     "failure rate reached",
+			cfg:      BreakerConfig{FailureRate: 0.5, MinRequests: 4},
+			results:  []error{nil, errDown, nil, errDown},
+			expected: StateOpen,
+		},
+		{
+			name:     "failure rate below MinRequests",
+			cfg:      BreakerConfig{FailureRate: 0.5, MinRequests: 4},
+			results:  []error{errDown, errDown, errDown},
+			expected: StateClosed,
+		},
+		{
+			name:     "failure rate not reached",
+			cfg:      BreakerConfig{FailureRate: 0.5, MinRequests: 4},
+			results:  []error{nil, nil, nil, errDown},
+			expected: StateClosed,
+		},
+		{
+			name:     "client errors and cancellation are not failures",
+			cfg:      BreakerConfig{ConsecutiveFailures: 2},
+			results:  []error{apperror.Err404RecordNotFound, fmt.Errorf("bind: %w", apperror.Err400InvalidData), context.Canceled},
+			expected: StateClosed,
+		},
+		{
+			name:     "cancellation does not reset consecutive failures",
+			cfg:      BreakerConfig{ConsecutiveFailures: 2},
+			results:  []error{errDown, context.Canceled, errDown},
+			expected: StateOpen,
+		},
+		{
+			name:     "cancellation is not counted in the failure rate",
+			cfg:      BreakerConfig{FailureRate: 0.5, MinRequests: 2},
+			results:  []error{errDown, context.Canceled},
+			expected: StateClosed,
+		},
+		{
+			name: "custom failure predicate",
+			cfg: BreakerConfig{
+				ConsecutiveFailures: 2,
+				IsFailure:           func(err error) bool { return errors.Is(err, apperror.Err404RecordNotFound) },
+			},
+			results:  []error{apperror.Err404RecordNotFound, apperror.Err404RecordNotFound},
+			expected: StateOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Clock = newBreakerClock()
+			b := NewBreaker(tt.cfg)
+
+			for _, err := range tt.results {
+				callBreaker(b, err)
+			}
+
+			assert.Equal(t, tt.expected, b.State())
+		})
+	}
+}
+
+func TestAsyncUtil_Breaker_RollingWindow(t *testing.T) {
+	clock := newBreakerClock()
+	b := NewBreaker(BreakerConfig{
+		FailureRate: 0.5,
+		MinRequests: 4,
+		Window:      10 * time.Second,
+		Buckets:     10,
+		Clock:       clock,
+	})
+
+	errDown := errors.New("dependency down")
+	callBreaker(b, errDown)
+	callBreaker(b, errDown)
+	callBreaker(b, errDown)
+
+	// The first failures slide out of the window before the rate is checked.
+	clock.Advance(15 * time.Second)
+	callBreaker(b, nil)
+	callBreaker(b, nil)
+	callBreaker(b, nil)
+	callBreaker(b, errDown)
+
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestAsyncUtil_Breaker_Lifecycle(t *testing.T) {
+	clock := newBreakerClock()
+
+	var mu sync.Mutex
+	var changes []string
+
+	b := NewBreaker(BreakerConfig{
+		Name:                "payments",
+		ConsecutiveFailures: 1,
+		Cooldown:            time.Minute,
+		HalfOpenMaxCalls:    2,
+		Clock:               clock,
+		OnStateChange: func(name string, from, to BreakerState) {
+			mu.Lock()
+			defer mu.Unlock()
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", name, from, to))
+		},
+	})
+
+	errDown := errors.New("dependency down")
+
+	// Closed -> open.
+	assert.ErrorIs(t, callBreaker(b, errDown), errDown)
+	assert.Equal(t, StateOpen, b.State())
+
+	// Open rejects calls without running them.
+	ran := false
+	_, err := WithBreaker(b, func() (int, error) {
+		ran = true
+		return 0, nil
+	})()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.ErrorIs(t, err, apperror.Err503ServiceUnavailable)
+	assert.False(t, ran)
+
+	// Open -> half-open after cooldown, then a failed probe opens it again.
+	clock.Advance(time.Minute)
+	assert.Equal(t, StateHalfOpen, b.State())
+	assert.ErrorIs(t, callBreaker(b, errDown), errDown)
+	assert.Equal(t, StateOpen, b.State())
+
+	// Half-open limits concurrent probes.
+	clock.Advance(time.Minute)
+	done1, err := b.Allow()
+	assert.NoError(t, err)
+	done2, err := b.Allow()
+	assert.NoError(t, err)
+	_, err = b.Allow()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	// Half-open -> closed once every probe succeeded.
+	done1(nil)
+	done1(errDown) // ignored, done only counts once
+	assert.Equal(t, StateHalfOpen, b.State())
+	done2(nil)
+	assert.Equal(t, StateClosed, b.State())
+
+	assert.Equal(t, []string{
+		"payments: closed -> open",
+		"payments: open -> half-open",
+		"payments: half-open -> open",
+		"payments: open -> half-open",
+		"payments: half-open -> closed",
+	}, changes)
+}
+
+func TestAsyncUtil_Breaker_CancelledProbe(t *testing.T) {
+	clock := newBreakerClock()
+	b := NewBreaker(BreakerConfig{ConsecutiveFailures: 1, Cooldown: time.Minute, Clock: clock})
+
+	callBreaker(b, errors.New("dependency down"))
+	clock.Advance(time.Minute)
+	assert.Equal(t, StateHalfOpen, b.State())
+
+	// A cancelled probe proves nothing and frees its slot for the next one.
+	assert.ErrorIs(t, callBreaker(b, fmt.Errorf("charge: %w", context.Canceled)), context.Canceled)
+	assert.Equal(t, StateHalfOpen, b.State())
+
+	done, err := b.Allow()
+	assert.NoError(t, err)
+	done(nil)
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestAsyncUtil_Breaker_StaleResults(t *testing.T) {
+	b := NewBreaker(BreakerConfig{ConsecutiveFailures: 1, Clock: newBreakerClock()})
+
+	done, err := b.Allow()
+	assert.NoError(t, err)
+
+	callBreaker(b, errors.New("dependency down"))
+	b.Reset()
+	assert.Equal(t, StateClosed, b.State())
+
+	// A result from before Reset does not count.
+	done(errors.New("late failure"))
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestAsyncUtil_Breaker_CallbackCanUseBreaker(t *testing.T) {
+	var b *Breaker
+	var seen BreakerState
+
+	b = NewBreaker(BreakerConfig{
+		ConsecutiveFailures: 1,
+		Clock:               newBreakerClock(),
+		OnStateChange: func(_ string, _, _ BreakerState) {
+			seen = b.State()
+		},
+	})
+
+	callBreaker(b, errors.New("dependency down"))
+	assert.Equal(t, StateOpen, seen)
+}
+
+func TestAsyncUtil_WithBreaker(t *testing.T) {
+	t.Run("through SafeGo", func(t *testing.T) {
+		b := NewBreaker(BreakerConfig{Clock: newBreakerClock()})
+
+		res := <-SafeGo(WithBreaker(b, func() (string, error) {
+			return "ok", nil
+		}))
+
+		assert.NoError(t, res.Err)
+		assert.Equal(t, "ok", res.Value)
+	})
+
+	t.Run("panic counts as failure", func(t *testing.T) {
+		b := NewBreaker(BreakerConfig{ConsecutiveFailures: 1, Clock: newBreakerClock()})
+
+		res := <-SafeGo(WithBreaker(b, func() (string, error) {
+			panic("breaker boom")
+		}))
+
+		assert.ErrorContains(t, res.Err, "panic recovered: breaker boom")
+		assert.Equal(t, StateOpen, b.State())
+	})
+
+	t.Run("context-aware with Retry", func(t *testing.T) {
+		b := NewBreaker(BreakerConfig{ConsecutiveFailures: 2, Clock: newBreakerClock()})
+		policy := RetryPolicy{MaxAttempts: 5, Clock: newInstantClock()}
+
+		calls := 0
+		_, err := Retry(context.Background(), policy, WithBreakerCtx(b, func(ctx context.Context) (int, error) {
+			calls++
+			return 0, errors.New("dependency down")
+		}))
+
+		// The third attempt is rejected by the open breaker.
+		assert.Equal(t, 2, calls)
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+	})
+}
+
+func TestAsyncUtil_BreakerState_String(t *testing.T) {
+	assert.Equal(t, "closed", StateClosed.String())
+	assert.Equal(t, "open", StateOpen.String())
+	assert.Equal(t, "half-open", StateHalfOpen.String())
+	assert.Equal(t, "BreakerState(9)", BreakerState(9).String())
+}
+
+func BenchmarkAsyncUtil_Breaker(b *testing.B) {
+	breaker := NewBreaker(BreakerConfig{FailureRate: 0.5})
+	fn := WithBreaker(breaker, func() (int, error) { return 1, nil })
+
+	for b.Loop() {
+		fn()
+	}
+}
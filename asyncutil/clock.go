@@ -0,0 +1,117 @@
+package asyncutil
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock abstracts time so timing-dependent helpers can be tested without sleeping.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// RealClock is the Clock backed by the time package. It is used when no clock is configured.
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// clockOrReal returns c, or RealClock when c is nil.
+func clockOrReal(c Clock) Clock {
+	if c == nil {
+		return RealClock
+	}
+	return c
+}
+
+// FakeClock is a manually driven Clock for tests. Time only moves when Advance
+// is called, which fires every After channel whose deadline has passed.
+//
+// Example:
+//
+//	clock := NewFakeClock(time.Now())
+//	d := Debounce(time.Second, save, clock)
+//
+//	d.Call()
+//	clock.Advance(time.Second) // save runs
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewFakeClock returns a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// After returns a channel that receives the fake time once Advance moves the
+// clock to now+d or later. A d <= 0 fires immediately.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+
+	c.waiters = append(c.waiters, fakeWaiter{deadline: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward by d and fires the due After channels in
+// deadline order.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	slices.SortStableFunc(c.waiters, func(a, b fakeWaiter) int {
+		return a.deadline.Compare(b.deadline)
+	})
+
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- w.deadline
+	}
+	clear(c.waiters[len(pending):])
+	c.waiters = pending
+}
+
+// Waiters returns the number of After channels that have not fired yet. Tests
+// use it to wait until a goroutine is blocked on the clock before advancing it.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.waiters)
+}
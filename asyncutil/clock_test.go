@@ -0,0 +1,101 @@
+package asyncutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsyncUtil_FakeClock(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		afters   []time.Duration
+		advance  time.Duration
+		expected []bool // whether each After channel fired
+		waiters  int
+	}{
+		{
+			name:     "fires due channels only",
+			afters:   []time.Duration{time.Second, 2 * time.Second, 3 * time.Second},
+			advance:  2 * time.Second,
+			expected: []bool{true, true, false},
+			waiters:  1,
+		},
+		{
+			name:     "non-positive duration fires immediately",
+			afters:   []time.Duration{0, -time.Second},
+			advance:  0,
+			expected: []bool{true, true},
+			waiters:  0,
+		},
+		{
+			name:     "nothing due",
+			afters:   []time.Duration{time.Minute},
+			advance:  time.Second,
+			expected: []bool{false},
+			waiters:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewFakeClock(start)
+
+			chans := make([]<-chan time.Time, len(tt.afters))
+			for i, d := range tt.afters {
+				chans[i] = clock.After(d)
+			}
+
+			clock.Advance(tt.advance)
+
+			fired := make([]bool, len(chans))
+			for i, ch := range chans {
+				select {
+				case <-ch:
+					fired[i] = true
+				default:
+				}
+			}
+
+			assert.Equal(t, tt.expected, fired)
+			assert.Equal(t, tt.waiters, clock.Waiters())
+			assert.Equal(t, start.Add(tt.advance), clock.Now())
+		})
+	}
+}
+
+func TestAsyncUtil_FakeClock_FiresAtDeadline(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	ch := clock.After(time.Second)
+	clock.Advance(time.Hour)
+
+	assert.Equal(t, start.Add(time.Second), <-ch)
+}
+
+func TestAsyncUtil_RealClock(t *testing.T) {
+	assert.WithinDuration(t, time.Now(), RealClock.Now(), time.Second)
+
+	select {
+	case <-RealClock.After(time.Millisecond):
+	case <-time.After(time.Second):
+		t.Fatal("RealClock.After did not fire")
+	}
+
+	assert.Equal(t, RealClock, clockOrReal(nil))
+}
+
+func BenchmarkAsyncUtil_FakeClock_Advance(b *testing.B) {
+	clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	for b.Loop() {
+		for range 10 {
+			clock.After(time.Second)
+		}
+		clock.Advance(time.Second)
+	}
+}
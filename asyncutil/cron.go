@@ -0,0 +1,265 @@
+package asyncutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when a scheduled job runs next.
+type Schedule interface {
+	// Next returns the first run time strictly after t, or the zero time if
+	// there is none.
+	Next(t time.Time) time.Time
+}
+
+type intervalSchedule time.Duration
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	if s <= 0 {
+		return time.Time{}
+	}
+	return t.Add(time.Duration(s))
+}
+
+// Every returns a Schedule that runs every d, counted from the end of the
+// previous wait. A d <= 0 never runs.
+//
+// Example:
+//
+//	scheduler.Add(JobConfig{Name: "heartbeat", Schedule: Every(30 * time.Second)}, sendHeartbeat)
+func Every(d time.Duration) Schedule {
+	return intervalSchedule(d)
+}
+
+// CronSchedule is a Schedule parsed from a standard 5-field cron expression.
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+	timeStar                      bool // minute or hour is a wildcard
+	loc                           *time.Location
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a standard 5-field cron expression: minute, hour, day of
+// month, month and day of week. Fields accept `*`, values, ranges (`1-5`),
+// lists (`1,15`), steps (`*/10`, `0-30/5`) and the names JAN-DEC and SUN-SAT;
+// both 0 and 7 mean Sunday. The macros @yearly, @monthly, @weekly, @daily and
+// @hourly are supported as well.
+//
+// Like cron, when both day of month and day of week are restricted, a day
+// matching either one runs the job.
+//
+// Times are evaluated in loc, so "0 9 * * MON-FRI" means 09:00 local to loc
+// on weekdays. A nil loc uses time.Local. Local times that do not exist on a
+// daylight saving change are skipped. Local times that occur twice, when the
+// clocks fall back, run only the first time, so "30 1 * * *" runs once that
+// night. Like cron, jobs with a wildcard minute or hour, e.g. "*/15 * * * *",
+// keep running through the repeated hour instead.
+//
+// Example:
+//
+//	paris, _ := time.LoadLocation("Europe/Paris")
+//	schedule, err := ParseCron("30 2 * * *", paris) // every day at 02:30 Paris time
+func ParseCron(expr string, loc *time.Location) (*CronSchedule, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	spec := strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("asyncutil: invalid cron expression %q: expected 5 fields, got %d", expr, len(parts))
+	}
+
+	bits := make([]uint64, len(parts))
+	for i, part := range parts {
+		b, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("asyncutil: invalid cron expression %q: %w", expr, err)
+		}
+		bits[i] = b
+	}
+
+	// Sunday can be written as 7.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	return &CronSchedule{
+		minute:   bits[0],
+		hour:     bits[1],
+		dom:      bits[2],
+		month:    bits[3],
+		dow:      bits[4],
+		domStar:  strings.HasPrefix(parts[2], "*"),
+		dowStar:  strings.HasPrefix(parts[4], "*"),
+		timeStar: strings.HasPrefix(parts[0], "*") || strings.HasPrefix(parts[1], "*"),
+		loc:      loc,
+	}, nil
+}
+
+// MustParseCron is like ParseCron but panics if the expression is invalid.
+// It is meant for expressions known at compile time.
+func MustParseCron(expr string, loc *time.Location) *CronSchedule {
+	s, err := ParseCron(expr, loc)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func parseCronField(part string, f cronField) (uint64, error) {
+	var bits uint64
+
+	for _, item := range strings.Split(part, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s: invalid step %q", f.name, stepPart)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rangePart == "*":
+			if f.name == "day of week" {
+				hi = 6
+			}
+		case strings.Contains(rangePart, "-"):
+			loPart, hiPart, _ := strings.Cut(rangePart, "-")
+
+			var err error
+			if lo, err = parseCronValue(loPart, f); err != nil {
+				return 0, err
+			}
+			if hi, err = parseCronValue(hiPart, f); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("%s: invalid range %q", f.name, rangePart)
+			}
+		default:
+			v, err := parseCronValue(rangePart, f)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if !hasStep {
+				hi = v
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseCronValue(s string, f cronField) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s: value %q out of range %d-%d", f.name, s, f.min, f.max)
+	}
+
+	return v, nil
+}
+
+// Next returns the first matching minute strictly after t, or the zero time if
+// none is found within 5 years.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	t = t.In(s.loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + 5
+
+	for t.Year() <= limit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.loc)
+			continue
+		}
+
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.loc)
+			continue
+		}
+
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = t.Add(time.Hour - time.Duration(t.Minute())*time.Minute)
+			continue
+		}
+
+		if s.minute&(1<<uint(t.Minute())) == 0 || !s.timeStar && isRepeatedWallTime(t) {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// isRepeatedWallTime reports whether the wall clock time of t already occurred
+// earlier that day, because the clocks fell back in the last few hours.
+func isRepeatedWallTime(t time.Time) bool {
+	_, before := t.Add(-3 * time.Hour).Zone()
+	_, offset := t.Zone()
+	if before <= offset {
+		return false
+	}
+
+	earlier := t.Add(-time.Duration(before-offset) * time.Second)
+	return earlier.Day() == t.Day() && earlier.Hour() == t.Hour() && earlier.Minute() == t.Minute()
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
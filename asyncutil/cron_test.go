@@ -0,0 +1,147 @@
+package asyncutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsyncUtil_ParseCron_Next(t *testing.T) {
+	// Thursday.
+	from := time.Date(2026, 1, 15, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		expr     string
+		from     time.Time
+		expected time.Time
+	}{
+		{"every minute", "* * * * *", from, time.Date(2026, 1, 15, 10, 18, 0, 0, time.UTC)},
+		{"every 15 minutes", "*/15 * * * *", from, time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)},
+		{"daily at 02:30", "30 2 * * *", from, time.Date(2026, 1, 16, 2, 30, 0, 0, time.UTC)},
+		{"list of hours", "0 9,12,18 * * *", from, time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)},
+		{"range with step", "0 8-18/4 * * *", from, time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)},
+		{"weekdays by name", "0 9 * * MON-FRI", time.Date(2026, 1, 16, 10, 0, 0, 0, time.UTC), time.Date(2026, 1, 19, 9, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 0 * * 7", from, time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"first of month", "0 0 1 * *", from, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"month by name", "0 0 1 jun *", from, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", from, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"day of month or day of week", "0 0 20 * MON", from, time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"exact match is skipped", "18 10 * * *", time.Date(2026, 1, 15, 10, 18, 0, 0, time.UTC), time.Date(2026, 1, 16, 10, 18, 0, 0, time.UTC)},
+		{"hourly macro", "@hourly", from, time.Date(2026, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"weekly macro", "@weekly", from, time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"never matches", "0 0 31 2 *", from, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr, time.UTC)
+			require.NoError(t, err)
+
+			next := schedule.Next(tt.from)
+			assert.True(t, tt.expected.Equal(next), "expected %v, got %v", tt.expected, next)
+		})
+	}
+}
+
+func TestAsyncUtil_ParseCron_TimeZone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	tests := []struct {
+		name     string
+		expr     string
+		from     time.Time
+		expected time.Time
+	}{
+		{
+			name:     "evaluated in the location",
+			expr:     "0 9 * * *",
+			from:     time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC), // 07:00 in New York
+			expected: time.Date(2026, 1, 15, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "skipped hour on spring forward",
+			expr:     "30 2 * * *",
+			from:     time.Date(2026, 3, 8, 0, 0, 0, 0, newYork),
+			expected: time.Date(2026, 3, 9, 2, 30, 0, 0, newYork),
+		},
+		{
+			name:     "repeated hour on fall back runs once",
+			expr:     "30 1 * * *",
+			from:     time.Date(2026, 11, 1, 5, 45, 0, 0, time.UTC), // 01:45 EDT, before the clocks fall back
+			expected: time.Date(2026, 11, 2, 1, 30, 0, 0, newYork),
+		},
+		{
+			name:     "first pass of the repeated hour",
+			expr:     "30 1 * * *",
+			from:     time.Date(2026, 11, 1, 0, 0, 0, 0, newYork),
+			expected: time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC), // 01:30 EDT
+		},
+		{
+			name:     "wildcard hour keeps running through the repeated hour",
+			expr:     "*/30 * * * *",
+			from:     time.Date(2026, 11, 1, 5, 45, 0, 0, time.UTC),
+			expected: time.Date(2026, 11, 1, 6, 0, 0, 0, time.UTC), // 01:00 EST
+		},
+		{
+			name:     "after the fall back",
+			expr:     "0 3 * * *",
+			from:     time.Date(2026, 11, 1, 0, 0, 0, 0, newYork),
+			expected: time.Date(2026, 11, 1, 3, 0, 0, 0, newYork),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := MustParseCron(tt.expr, newYork)
+
+			next := schedule.Next(tt.from)
+			assert.True(t, tt.expected.Equal(next), "expected %v, got %v", tt.expected, next)
+		})
+	}
+}
+
+func TestAsyncUtil_ParseCron_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		expr        string
+		errContains string
+	}{
+		{"too few fields", "* * * *", "expected 5 fields, got 4"},
+		{"too many fields", "0 * * * * *", "expected 5 fields, got 6"},
+		{"minute out of range", "60 * * * *", `minute: value "60" out of range 0-59`},
+		{"unknown name", "0 0 * * FUNDAY", `day of week: value "FUNDAY" out of range 0-7`},
+		{"zero step", "*/0 * * * *", `minute: invalid step "0"`},
+		{"reversed range", "0 18-9 * * *", `hour: invalid range "18-9"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCron(tt.expr, nil)
+			assert.ErrorContains(t, err, tt.errContains)
+		})
+	}
+
+	assert.Panics(t, func() { MustParseCron("bad", nil) })
+}
+
+func TestAsyncUtil_Every(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, from.Add(time.Minute), Every(time.Minute).Next(from))
+	assert.True(t, Every(0).Next(from).IsZero())
+}
+
+func BenchmarkAsyncUtil_CronSchedule_Next(b *testing.B) {
+	schedule := MustParseCron("0 9 * * MON-FRI", time.UTC)
+	from := time.Date(2026, 1, 15, 10, 17, 0, 0, time.UTC)
+
+	for b.Loop() {
+		schedule.Next(from)
+	}
+}
@@ -0,0 +1,177 @@
+package asyncutil
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Debouncer delays a function until calls have stopped for a while.
+// See Debounce.
+type Debouncer struct {
+	wait  time.Duration
+	fn    func()
+	clock Clock
+
+	mu       sync.Mutex
+	deadline time.Time
+	pending  bool
+	waiting  bool
+}
+
+// Debounce returns a Debouncer that runs fn once wait has passed since the last
+// Call. A burst of calls results in a single run after the burst ends. fn runs
+// in a background goroutine; panics are recovered and reported to OnPanic.
+// A nil clock uses RealClock.
+//
+// Example:
+//
+//	// Rebuild the search index once edits have settled for 2 seconds.
+//	reindex := Debounce(2*time.Second, func() {
+//	    index.Rebuild()
+//	}, nil)
+//
+//	for range edits {
+//	    reindex.Call()
+//	}
+func Debounce(wait time.Duration, fn func(), clock Clock) *Debouncer {
+	return &Debouncer{wait: wait, fn: fn, clock: clockOrReal(clock)}
+}
+
+// Call schedules fn to run after wait, pushing back any pending run.
+func (d *Debouncer) Call() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.deadline = d.clock.Now().Add(d.wait)
+	d.pending = true
+
+	if !d.waiting {
+		d.waiting = true
+		go d.loop()
+	}
+}
+
+// Flush runs fn now if a run is pending and cancels the scheduled run.
+// It reports whether fn ran.
+func (d *Debouncer) Flush() bool {
+	d.mu.Lock()
+	pending := d.pending
+	d.pending = false
+	d.mu.Unlock()
+
+	if pending {
+		runSafely(d.fn)
+	}
+	return pending
+}
+
+// Cancel drops the pending run, if any.
+func (d *Debouncer) Cancel() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.pending = false
+}
+
+// loop sleeps until the latest deadline, which Call may keep moving, then runs fn.
+func (d *Debouncer) loop() {
+	for {
+		d.mu.Lock()
+		if !d.pending {
+			d.waiting = false
+			d.mu.Unlock()
+			return
+		}
+
+		remaining := d.deadline.Sub(d.clock.Now())
+		if remaining <= 0 {
+			d.pending = false
+			d.waiting = false
+			d.mu.Unlock()
+
+			runSafely(d.fn)
+			return
+		}
+		d.mu.Unlock()
+
+		<-d.clock.After(remaining)
+	}
+}
+
+// Throttler limits a function to one run per interval. See Throttle.
+type Throttler struct {
+	interval time.Duration
+	fn       func()
+	clock    Clock
+
+	mu        sync.Mutex
+	last      time.Time
+	ran       bool
+	scheduled bool
+}
+
+// Throttle returns a Throttler that runs fn at most once per interval. The first
+// Call runs fn right away in the caller's goroutine; calls during the interval
+// are coalesced into a single trailing run when it ends, in a background
+// goroutine. Panics in fn are recovered and reported to OnPanic.
+// A nil clock uses RealClock.
+//
+// Example:
+//
+//	// Push progress to the client at most every 500ms.
+//	notify := Throttle(500*time.Millisecond, func() {
+//	    ws.Send(progress.Snapshot())
+//	}, nil)
+//
+//	for row := range rows {
+//	    progress.Add(row)
+//	    notify.Call()
+//	}
+func Throttle(interval time.Duration, fn func(), clock Clock) *Throttler {
+	return &Throttler{interval: interval, fn: fn, clock: clockOrReal(clock)}
+}
+
+// Call runs fn now if the interval since the last run has passed, and
+// otherwise schedules a trailing run.
+func (t *Throttler) Call() {
+	t.mu.Lock()
+
+	if t.scheduled {
+		t.mu.Unlock()
+		return
+	}
+
+	now := t.clock.Now()
+	if !t.ran || now.Sub(t.last) >= t.interval {
+		t.last = now
+		t.ran = true
+		t.mu.Unlock()
+
+		runSafely(t.fn)
+		return
+	}
+
+	t.scheduled = true
+	delay := t.interval - now.Sub(t.last)
+	t.mu.Unlock()
+
+	go func() {
+		<-t.clock.After(delay)
+
+		t.mu.Lock()
+		t.scheduled = false
+		t.last = t.clock.Now()
+		t.mu.Unlock()
+
+		runSafely(t.fn)
+	}()
+}
+
+// runSafely calls fn, recovering and reporting a panic like SafeGo.
+func runSafely(fn func()) {
+	safeCall(context.Background(), func() (struct{}, error) {
+		fn()
+		return struct{}{}, nil
+	})
+}
@@ -0,0 +1,136 @@
+package asyncutil
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitForWaiters blocks until n goroutines are waiting on clock.
+func waitForWaiters(t *testing.T, clock *FakeClock, n int) {
+	t.Helper()
+	assert.Eventually(t, func() bool { return clock.Waiters() == n }, time.Second, time.Millisecond)
+}
+
+func TestAsyncUtil_Debounce(t *testing.T) {
+	clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var runs atomic.Int32
+	d := Debounce(time.Second, func() { runs.Add(1) }, clock)
+
+	d.Call()
+	waitForWaiters(t, clock, 1)
+
+	// A call within the wait pushes the run back.
+	clock.Advance(500 * time.Millisecond)
+	d.Call()
+	clock.Advance(500 * time.Millisecond)
+	waitForWaiters(t, clock, 1)
+	assert.Equal(t, int32(0), runs.Load())
+
+	clock.Advance(500 * time.Millisecond)
+	assert.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, time.Millisecond)
+
+	// A new burst schedules a new run.
+	d.Call()
+	d.Call()
+	waitForWaiters(t, clock, 1)
+	clock.Advance(time.Second)
+	assert.Eventually(t, func() bool { return runs.Load() == 2 }, time.Second, time.Millisecond)
+}
+
+func TestAsyncUtil_Debounce_FlushAndCancel(t *testing.T) {
+	tests := []struct {
+		name         string
+		action       func(d *Debouncer) bool
+		expectedRan  bool
+		expectedRuns int32
+	}{
+		{
+			name:         "flush runs pending call now",
+			action:       func(d *Debouncer) bool { return d.Flush() },
+			expectedRan:  true,
+			expectedRuns: 1,
+		},
+		{
+			name:         "cancel drops pending call",
+			action:       func(d *Debouncer) bool { d.Cancel(); return false },
+			expectedRan:  false,
+			expectedRuns: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+			var runs atomic.Int32
+			d := Debounce(time.Second, func() { runs.Add(1) }, clock)
+
+			d.Call()
+			waitForWaiters(t, clock, 1)
+
+			assert.Equal(t, tt.expectedRan, tt.action(d))
+
+			// The scheduled run does not happen afterwards.
+			clock.Advance(time.Second)
+			waitForWaiters(t, clock, 0)
+			time.Sleep(5 * time.Millisecond)
+			assert.Equal(t, tt.expectedRuns, runs.Load())
+			assert.False(t, d.Flush(), "nothing left to flush")
+		})
+	}
+}
+
+func TestAsyncUtil_Debounce_Panic(t *testing.T) {
+	panicked := make(chan error, 1)
+	OnPanic = func(err error) { panicked <- err }
+	defer func() { OnPanic = nil }()
+
+	d := Debounce(time.Millisecond, func() { panic("debounce boom") }, nil)
+	d.Call()
+
+	select {
+	case err := <-panicked:
+		assert.ErrorContains(t, err, "panic recovered: debounce boom")
+	case <-time.After(time.Second):
+		t.Fatal("panic was not reported")
+	}
+}
+
+func TestAsyncUtil_Throttle(t *testing.T) {
+	clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var runs atomic.Int32
+	th := Throttle(time.Second, func() { runs.Add(1) }, clock)
+
+	// Leading call runs right away.
+	th.Call()
+	assert.Equal(t, int32(1), runs.Load())
+
+	// Calls within the interval are coalesced into one trailing run.
+	clock.Advance(200 * time.Millisecond)
+	th.Call()
+	th.Call()
+	th.Call()
+	waitForWaiters(t, clock, 1)
+	assert.Equal(t, int32(1), runs.Load())
+
+	clock.Advance(800 * time.Millisecond)
+	assert.Eventually(t, func() bool { return runs.Load() == 2 }, time.Second, time.Millisecond)
+
+	// After a full interval the next call runs right away again.
+	clock.Advance(time.Second)
+	th.Call()
+	assert.Equal(t, int32(3), runs.Load())
+}
+
+func BenchmarkAsyncUtil_Throttle_Call(b *testing.B) {
+	th := Throttle(time.Hour, func() {}, nil)
+
+	for b.Loop() {
+		th.Call()
+	}
+}
@@ -0,0 +1,185 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoFutures is returned by Any and Race when they are called without channels.
+var ErrNoFutures = errors.New("asyncutil: no futures given")
+
+// All waits for every channel and returns the values in argument order.
+// It returns early with the first failure, wrapped in *IndexError, or with
+// ctx.Err() if the context is done first.
+//
+// Example:
+//
+//	userCh := SafeGoCtx(ctx, loadUser)
+//	prefsCh := SafeGoCtx(ctx, loadPrefs)
+//
+//	results, err := All(ctx, userCh, prefsCh)
+func All[T any](ctx context.Context, chans ...<-chan Result[T]) ([]T, error) {
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	values := make([]T, len(chans))
+	results := merge(ctx, chans)
+
+	for range chans {
+		select {
+		case res := <-results:
+			if res.Err != nil {
+				return values, &IndexError{Index: res.index, Err: res.Err}
+			}
+			values[res.index] = res.Value
+		case <-ctx.Done():
+			return values, ctx.Err()
+		}
+	}
+
+	return values, nil
+}
+
+// Any returns the first successful value. If every channel fails, it returns
+// the failures joined with errors.Join, each wrapped in *IndexError.
+//
+// Example:
+//
+//	// Ask every replica, keep the first answer.
+//	rate, err := Any(ctx,
+//	    SafeGoCtx(ctx, primary.Rate),
+//	    SafeGoCtx(ctx, replica.Rate),
+//	)
+func Any[T any](ctx context.Context, chans ...<-chan Result[T]) (T, error) {
+	var zero T
+	if len(chans) == 0 {
+		return zero, ErrNoFutures
+	}
+
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	results := merge(ctx, chans)
+	errs := make([]error, 0, len(chans))
+
+	for range chans {
+		select {
+		case res := <-results:
+			if res.Err == nil {
+				return res.Value, nil
+			}
+			errs = append(errs, &IndexError{Index: res.index, Err: res.Err})
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+
+	return zero, errors.Join(errs...)
+}
+
+// Race returns the result of the first channel to complete, whether it
+// succeeded or failed.
+//
+// Example:
+//
+//	val, err := Race(ctx, SafeGoCtx(ctx, fetchFromCache), SafeGoCtx(ctx, fetchFromDB))
+func Race[T any](ctx context.Context, chans ...<-chan Result[T]) (T, error) {
+	var zero T
+	if len(chans) == 0 {
+		return zero, ErrNoFutures
+	}
+
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	select {
+	case res := <-merge(ctx, chans):
+		return res.Value, res.Err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// Then returns a channel with the result of fn applied to the value of ch.
+// Errors from ch are passed through without calling fn, and fn is not called
+// if ctx is done first. Panics in fn are recovered like SafeGo.
+//
+// Example:
+//
+//	userCh := SafeGoCtx(ctx, loadUser)
+//	nameCh := Then(ctx, userCh, func(ctx context.Context, u User) (string, error) {
+//	    return u.Name, nil
+//	})
+func Then[T any, R any](ctx context.Context, ch <-chan Result[T], fn func(ctx context.Context, val T) (R, error)) <-chan Result[R] {
+	out := make(chan Result[R], 1)
+
+	go func() {
+		val, err := Await(ctx, ch)
+		if err != nil {
+			var zero R
+			out <- Result[R]{Value: zero, Err: err}
+			return
+		}
+
+		next, err := safeCall(ctx, func() (R, error) { return fn(ctx, val) })
+		out <- Result[R]{Value: next, Err: err}
+	}()
+
+	return out
+}
+
+// WithTimeout returns a channel with the result of ch, or context.DeadlineExceeded
+// if ch does not complete within d.
+//
+// Example:
+//
+//	results, err := All(ctx,
+//	    WithTimeout(SafeGoCtx(ctx, loadUser), time.Second),
+//	    WithTimeout(SafeGoCtx(ctx, loadPrefs), 200*time.Millisecond),
+//	)
+func WithTimeout[T any](ch <-chan Result[T], d time.Duration) <-chan Result[T] {
+	out := make(chan Result[T], 1)
+
+	go func() {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case res := <-ch:
+			out <- res
+		case <-timer.C:
+			var zero T
+			out <- Result[T]{Value: zero, Err: context.DeadlineExceeded}
+		}
+	}()
+
+	return out
+}
+
+type indexedResult[T any] struct {
+	Result[T]
+	index int
+}
+
+// merge forwards every result into a single channel, tagged with its argument index.
+// Forwarders stop when ctx is done, so callers cancel it once they return to
+// release the ones whose source never completes.
+func merge[T any](ctx context.Context, chans []<-chan Result[T]) <-chan indexedResult[T] {
+	out := make(chan indexedResult[T], len(chans))
+
+	for i, ch := range chans {
+		go func() {
+			select {
+			case res := <-ch:
+				select {
+				case out <- indexedResult[T]{Result: res, index: i}:
+				case <-ctx.Done():
+				}
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	return out
+}
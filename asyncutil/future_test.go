@@ -0,0 +1,349 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resolved returns a channel that yields res after delay.
+func resolved[T any](res Result[T], delay time.Duration) <-chan Result[T] {
+	ch := make(chan Result[T], 1)
+	go func() {
+		time.Sleep(delay)
+		ch <- res
+	}()
+	return ch
+}
+
+// pending returns a channel that never yields.
+func pending[T any]() <-chan Result[T] {
+	return make(chan Result[T])
+}
+
+func TestAsyncUtil_All(t *testing.T) {
+	errBad := errors.New("bad")
+
+	tests := []struct {
+		name          string
+		chans         func() []<-chan Result[int]
+		timeout       time.Duration
+		expected      []int
+		expectedErr   error
+		expectedIndex int
+	}{
+		{
+			name: "keeps argument order",
+			chans: func() []<-chan Result[int] {
+				return []<-chan Result[int]{
+					resolved(Result[int]{Value: 1}, 20*time.Millisecond),
+					resolved(Result[int]{Value: 2}, 0),
+					resolved(Result[int]{Value: 3}, 10*time.Millisecond),
+				}
+			},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "no channels",
+			chans:    func() []<-chan Result[int] { return nil },
+			expected: []int{},
+		},
+		{
+			name: "fails fast",
+			chans: func() []<-chan Result[int] {
+				return []<-chan Result[int]{
+					pending[int](),
+					resolved(Result[int]{Err: errBad}, 0),
+				}
+			},
+			expected:      []int{0, 0},
+			expectedErr:   errBad,
+			expectedIndex: 1,
+		},
+		{
+			name: "context done",
+			chans: func() []<-chan Result[int] {
+				return []<-chan Result[int]{resolved(Result[int]{Value: 1}, 0), pending[int]()}
+			},
+			timeout:     20 * time.Millisecond,
+			expectedErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			values, err := All(ctx, tt.chans()...)
+
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, values)
+				return
+			}
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			if tt.expected != nil {
+				assert.Equal(t, tt.expected, values)
+
+				var idxErr *IndexError
+				require.ErrorAs(t, err, &idxErr)
+				assert.Equal(t, tt.expectedIndex, idxErr.Index)
+			}
+		})
+	}
+}
+
+func TestAsyncUtil_Any(t *testing.T) {
+	errA := errors.New("replica a down")
+	errB := errors.New("replica b down")
+
+	tests := []struct {
+		name        string
+		chans       []<-chan Result[string]
+		expected    string
+		expectedErr []error
+	}{
+		{
+			name: "first success wins over earlier failure",
+			chans: []<-chan Result[string]{
+				resolved(Result[string]{Err: errA}, 0),
+				resolved(Result[string]{Value: "b"}, 10*time.Millisecond),
+				pending[string](),
+			},
+			expected: "b",
+		},
+		{
+			name: "joins errors when all fail",
+			chans: []<-chan Result[string]{
+				resolved(Result[string]{Err: errA}, 0),
+				resolved(Result[string]{Err: errB}, 5*time.Millisecond),
+			},
+			expectedErr: []error{errA, errB},
+		},
+		{
+			name:        "no channels",
+			expectedErr: []error{ErrNoFutures},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val, err := Any(context.Background(), tt.chans...)
+
+			assert.Equal(t, tt.expected, val)
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			for _, target := range tt.expectedErr {
+				assert.ErrorIs(t, err, target)
+			}
+		})
+	}
+
+	t.Run("context cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := Any(ctx, pending[string]())
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestAsyncUtil_Race(t *testing.T) {
+	errFast := errors.New("fast failure")
+
+	tests := []struct {
+		name        string
+		chans       []<-chan Result[int]
+		expected    int
+		expectedErr error
+	}{
+		{
+			name: "first value",
+			chans: []<-chan Result[int]{
+				resolved(Result[int]{Value: 1}, 30*time.Millisecond),
+				resolved(Result[int]{Value: 2}, 0),
+			},
+			expected: 2,
+		},
+		{
+			name: "first failure",
+			chans: []<-chan Result[int]{
+				resolved(Result[int]{Value: 1}, 30*time.Millisecond),
+				resolved(Result[int]{Err: errFast}, 0),
+			},
+			expectedErr: errFast,
+		},
+		{
+			name:        "no channels",
+			expectedErr: ErrNoFutures,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val, err := Race(context.Background(), tt.chans...)
+
+			assert.Equal(t, tt.expected, val)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := Race(ctx, pending[int]())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestAsyncUtil_Combinators_ReleaseForwarders(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	All(ctx, pending[int](), pending[int]())
+	Any(context.Background(), resolved(Result[int]{Value: 1}, 0), pending[int]())
+	Race(context.Background(), resolved(Result[int]{Value: 1}, 0), pending[int]())
+
+	assert.Eventually(t, func() bool {
+		return runtime.NumGoroutine() <= before
+	}, time.Second, 5*time.Millisecond, "forwarders of never-completing sources should exit")
+}
+
+func TestAsyncUtil_Then(t *testing.T) {
+	errSource := errors.New("source failed")
+
+	tests := []struct {
+		name        string
+		ctx         func() (context.Context, context.CancelFunc)
+		source      <-chan Result[int]
+		fn          func(ctx context.Context, val int) (string, error)
+		expected    string
+		expectedErr error
+		errContains string
+		expectCall  bool
+	}{
+		{
+			name:   "transforms value",
+			source: SafeGo(func() (int, error) { return 21, nil }),
+			fn: func(ctx context.Context, val int) (string, error) {
+				return strconv.Itoa(val * 2), nil
+			},
+			expected:   "42",
+			expectCall: true,
+		},
+		{
+			name:        "passes source error through",
+			source:      resolved(Result[int]{Err: errSource}, 0),
+			expectedErr: errSource,
+		},
+		{
+			name:   "recovers panic in fn",
+			source: resolved(Result[int]{Value: 1}, 0),
+			fn: func(ctx context.Context, val int) (string, error) {
+				panic("then boom")
+			},
+			errContains: "panic recovered: then boom",
+			expectCall:  true,
+		},
+		{
+			name: "context done before source",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+			source:      pending[int](),
+			expectedErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.ctx != nil {
+				ctx, cancel = tt.ctx()
+			}
+			defer cancel()
+
+			called := false
+			fn := func(ctx context.Context, val int) (string, error) {
+				called = true
+				return tt.fn(ctx, val)
+			}
+
+			res := <-Then(ctx, tt.source, fn)
+
+			assert.Equal(t, tt.expected, res.Value)
+			assert.Equal(t, tt.expectCall, called)
+			switch {
+			case tt.expectedErr != nil:
+				assert.ErrorIs(t, res.Err, tt.expectedErr)
+			case tt.errContains != "":
+				assert.ErrorContains(t, res.Err, tt.errContains)
+			default:
+				assert.NoError(t, res.Err)
+			}
+		})
+	}
+}
+
+func TestAsyncUtil_WithTimeout(t *testing.T) {
+	t.Run("result before timeout", func(t *testing.T) {
+		res := <-WithTimeout(resolved(Result[string]{Value: "fast"}, 0), time.Second)
+
+		assert.NoError(t, res.Err)
+		assert.Equal(t, "fast", res.Value)
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		res := <-WithTimeout(pending[string](), 10*time.Millisecond)
+
+		assert.ErrorIs(t, res.Err, context.DeadlineExceeded)
+		assert.Empty(t, res.Value)
+	})
+
+	t.Run("composes with All", func(t *testing.T) {
+		_, err := All(context.Background(),
+			WithTimeout(resolved(Result[int]{Value: 1}, 0), time.Second),
+			WithTimeout(pending[int](), 10*time.Millisecond),
+		)
+
+		var idxErr *IndexError
+		require.ErrorAs(t, err, &idxErr)
+		assert.Equal(t, 1, idxErr.Index)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func BenchmarkAsyncUtil_All(b *testing.B) {
+	ctx := context.Background()
+
+	for b.Loop() {
+		chans := make([]<-chan Result[int], 10)
+		for i := range chans {
+			ch := make(chan Result[int], 1)
+			ch <- Result[int]{Value: i}
+			chans[i] = ch
+		}
+		All(ctx, chans...)
+	}
+}
@@ -0,0 +1,124 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Group runs related tasks concurrently and collects their results in the order
+// the tasks were added. The first failing task cancels the context shared by the
+// group, so siblings can stop early.
+//
+// Panics are recovered and reported to OnPanic, like SafeGo.
+//
+// Example:
+//
+//	g, ctx := NewGroup[Profile](r.Context())
+//	g.SetLimit(4)
+//
+//	for _, id := range ids {
+//	    g.Go(func(ctx context.Context) (Profile, error) {
+//	        return client.FetchProfile(ctx, id)
+//	    })
+//	}
+//
+//	profiles, err := g.Wait() // profiles[i] belongs to ids[i]
+type Group[T any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	sem    chan struct{}
+
+	mu      sync.Mutex
+	results []T
+	errs    []error
+	failed  bool
+}
+
+// NewGroup returns a Group and the context shared by its tasks. The context is
+// cancelled when a task fails or when Wait returns.
+func NewGroup[T any](ctx context.Context) (*Group[T], context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group[T]{ctx: ctx, cancel: cancel}, ctx
+}
+
+// SetLimit limits the number of tasks running at once. Go blocks while the limit
+// is reached. A limit <= 0 means no limit. It must be called before Go.
+func (g *Group[T]) SetLimit(n int) {
+	if n <= 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go runs fn in a new goroutine. Tasks added after the group context is done
+// are not started and report the context error.
+func (g *Group[T]) Go(fn func(ctx context.Context) (T, error)) {
+	g.mu.Lock()
+	index := len(g.results)
+	var zero T
+	g.results = append(g.results, zero)
+	g.mu.Unlock()
+
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		case <-g.ctx.Done():
+			g.record(index, zero, g.ctx.Err())
+			return
+		}
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+
+		if err := g.ctx.Err(); err != nil {
+			g.record(index, zero, err)
+			return
+		}
+
+		val, err := runTask(g.ctx, func() (T, error) { return fn(g.ctx) })
+		g.record(index, val, err)
+	}()
+}
+
+// Wait blocks until every task has finished and returns their results in the
+// order the tasks were added. Failed tasks leave the zero value in their slot.
+//
+// The returned error joins every task error with errors.Join. Cancellation
+// errors reported by siblings after the first failure are left out.
+func (g *Group[T]) Wait() ([]T, error) {
+	g.wg.Wait()
+	g.cancel()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.results, errors.Join(g.errs...)
+}
+
+func (g *Group[T]) record(index int, val T, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err == nil {
+		g.results[index] = val
+		return
+	}
+
+	if g.failed && errors.Is(err, context.Canceled) {
+		return
+	}
+
+	if !g.failed {
+		g.failed = true
+		g.cancel()
+	}
+	g.errs = append(g.errs, err)
+}
@@ -0,0 +1,173 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsyncUtil_Group(t *testing.T) {
+	errFirst := errors.New("first failure")
+
+	tests := []struct {
+		name        string
+		tasks       []func(ctx context.Context) (int, error)
+		expected    []int
+		expectedErr []error
+		errContains string
+	}{
+		{
+			name: "results in input order",
+			tasks: []func(ctx context.Context) (int, error){
+				func(ctx context.Context) (int, error) { time.Sleep(3 * time.Millisecond); return 1, nil },
+				func(ctx context.Context) (int, error) { return 2, nil },
+				func(ctx context.Context) (int, error) { time.Sleep(time.Millisecond); return 3, nil },
+			},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name: "first error cancels siblings",
+			tasks: []func(ctx context.Context) (int, error){
+				func(ctx context.Context) (int, error) { return 0, errFirst },
+				func(ctx context.Context) (int, error) { <-ctx.Done(); return 0, ctx.Err() },
+				func(ctx context.Context) (int, error) { <-ctx.Done(); return 3, ctx.Err() },
+			},
+			expected:    []int{0, 0, 0},
+			expectedErr: []error{errFirst},
+		},
+		{
+			name: "panic is recovered",
+			tasks: []func(ctx context.Context) (int, error){
+				func(ctx context.Context) (int, error) { panic("group boom") },
+			},
+			expected:    []int{0},
+			errContains: "panic recovered: group boom",
+		},
+		{
+			name:     "no tasks",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, _ := NewGroup[int](context.Background())
+			for _, task := range tt.tasks {
+				g.Go(task)
+			}
+
+			results, err := g.Wait()
+
+			assert.Equal(t, tt.expected, results)
+			switch {
+			case tt.expectedErr != nil:
+				for _, e := range tt.expectedErr {
+					assert.ErrorIs(t, err, e)
+				}
+				assert.NotErrorIs(t, err, context.Canceled)
+			case tt.errContains != "":
+				assert.ErrorContains(t, err, tt.errContains)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAsyncUtil_Group_JoinsAllErrors(t *testing.T) {
+	errA := errors.New("a failed")
+	errB := errors.New("b failed")
+	aStarted := make(chan struct{})
+	bStarted := make(chan struct{})
+
+	g, _ := NewGroup[int](context.Background())
+	g.Go(func(ctx context.Context) (int, error) {
+		close(aStarted)
+		<-bStarted
+		return 0, errA
+	})
+	g.Go(func(ctx context.Context) (int, error) {
+		<-aStarted
+		close(bStarted)
+		return 0, errB
+	})
+
+	_, err := g.Wait()
+
+	assert.ErrorIs(t, err, errA)
+	assert.ErrorIs(t, err, errB)
+}
+
+func TestAsyncUtil_Group_SetLimit(t *testing.T) {
+	g, _ := NewGroup[int](context.Background())
+	g.SetLimit(2)
+
+	var running, peak atomic.Int32
+	for i := range 10 {
+		g.Go(func(ctx context.Context) (int, error) {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			return i, nil
+		})
+	}
+
+	results, err := g.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, results)
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+}
+
+func TestAsyncUtil_Group_ContextCancelled(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	g, ctx := NewGroup[int](parent)
+	g.SetLimit(1)
+
+	cancel()
+	<-ctx.Done()
+
+	var ran atomic.Bool
+	g.Go(func(ctx context.Context) (int, error) {
+		ran.Store(true)
+		return 1, nil
+	})
+
+	results, err := g.Wait()
+
+	assert.Equal(t, []int{0}, results)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, ran.Load())
+}
+
+func TestAsyncUtil_Group_WaitCancelsContext(t *testing.T) {
+	g, ctx := NewGroup[int](context.Background())
+	g.Go(func(ctx context.Context) (int, error) { return 1, nil })
+
+	_, err := g.Wait()
+
+	assert.NoError(t, err)
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func BenchmarkAsyncUtil_Group(b *testing.B) {
+	for b.Loop() {
+		g, _ := NewGroup[int](context.Background())
+		for i := range 10 {
+			g.Go(func(ctx context.Context) (int, error) {
+				return i, nil
+			})
+		}
+		g.Wait()
+	}
+}
@@ -0,0 +1,290 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// LifecycleConfig configures a Lifecycle.
+type LifecycleConfig struct {
+	// ShutdownTimeout bounds the whole shutdown: stopping servers, waiting for
+	// tasks and running hooks. Defaults to 30 seconds.
+	ShutdownTimeout time.Duration
+
+	// Signals start the shutdown when received by Wait. Defaults to SIGINT and
+	// SIGTERM.
+	Signals []os.Signal
+}
+
+// ShutdownError reports what went wrong during a Lifecycle shutdown.
+type ShutdownError struct {
+	// Unfinished lists the tasks still running when ShutdownTimeout passed.
+	Unfinished []string
+
+	// Errors holds the failures of tasks, servers and hooks, each prefixed
+	// with its name.
+	Errors []error
+}
+
+func (e *ShutdownError) Error() string {
+	var parts []string
+	if len(e.Unfinished) > 0 {
+		parts = append(parts, fmt.Sprintf("tasks did not finish in time: %s", strings.Join(e.Unfinished, ", ")))
+	}
+	for _, err := range e.Errors {
+		parts = append(parts, err.Error())
+	}
+	return "shutdown: " + strings.Join(parts, "; ")
+}
+
+// Unwrap returns Errors, plus context.DeadlineExceeded when tasks were left
+// unfinished, for errors.Is and errors.As.
+func (e *ShutdownError) Unwrap() []error {
+	if len(e.Unfinished) == 0 {
+		return e.Errors
+	}
+	return append(slices.Clone(e.Errors), context.DeadlineExceeded)
+}
+
+// Lifecycle coordinates the graceful shutdown of a service. It tracks the
+// goroutines started with Go, the HTTP servers started with Serve and the
+// hooks registered with OnShutdown and AddCloser.
+//
+// Shutdown starts when Wait receives a signal, when the parent context is
+// done, when a task fails or panics, or when Shutdown is called. It then:
+//
+//  1. cancels the context passed to tasks,
+//  2. shuts down the HTTP servers, letting in-flight requests finish,
+//  3. waits for the tasks,
+//  4. runs the hooks in reverse order of registration, like defer.
+//
+// All steps share ShutdownTimeout. Tasks still running at the deadline are
+// reported in *ShutdownError.
+//
+// Example:
+//
+//	lc, ctx := NewLifecycle(context.Background(), LifecycleConfig{ShutdownTimeout: 15 * time.Second})
+//
+//	lc.AddCloser("db", db)
+//	lc.Go("outbox-relay", relay.Run)
+//	lc.Serve("api", &http.Server{Addr: ":8080", Handler: router})
+//
+//	if err := lc.Wait(); err != nil {
+//	    slog.ErrorContext(ctx, "unclean shutdown", "err", err)
+//	    os.Exit(1)
+//	}
+type Lifecycle struct {
+	cfg    LifecycleConfig
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+	tasks  sync.WaitGroup
+
+	mu       sync.Mutex
+	running  map[uint64]string
+	nextID   uint64
+	servers  []namedServer
+	hooks    []lifecycleHook
+	errs     []error
+	stopping bool
+
+	once sync.Once
+	err  error
+}
+
+type namedServer struct {
+	name string
+	srv  *http.Server
+}
+
+type lifecycleHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewLifecycle returns a Lifecycle and the context passed to its tasks. The
+// context is cancelled when shutdown starts.
+func NewLifecycle(ctx context.Context, cfg LifecycleConfig) (*Lifecycle, context.Context) {
+	if cfg.ShutdownTimeout <= 0 {
+		cfg.ShutdownTimeout = 30 * time.Second
+	}
+	if len(cfg.Signals) == 0 {
+		cfg.Signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	lctx, cancel := context.WithCancel(ctx)
+
+	return &Lifecycle{
+		cfg:     cfg,
+		parent:  ctx,
+		ctx:     lctx,
+		cancel:  cancel,
+		running: make(map[uint64]string),
+	}, lctx
+}
+
+// Go runs fn in a tracked goroutine. fn should return once its context is
+// cancelled. A task that returns an error or panics starts the shutdown; the
+// panic is recovered and reported to OnPanic like SafeGo.
+//
+// Tasks added after shutdown has started are not run.
+func (l *Lifecycle) Go(name string, fn func(ctx context.Context) error) {
+	l.mu.Lock()
+	if l.stopping {
+		l.mu.Unlock()
+		return
+	}
+	id := l.nextID
+	l.nextID++
+	l.running[id] = name
+	l.tasks.Add(1)
+	l.mu.Unlock()
+
+	go func() {
+		defer l.tasks.Done()
+
+		ctx := WithTaskName(l.ctx, name)
+		_, err := runTask(ctx, func() (struct{}, error) { return struct{}{}, fn(ctx) })
+
+		l.mu.Lock()
+		delete(l.running, id)
+		if err != nil && !(l.ctx.Err() != nil && errors.Is(err, context.Canceled)) {
+			l.errs = append(l.errs, fmt.Errorf("task %s: %w", name, err))
+		}
+		l.mu.Unlock()
+
+		if err != nil {
+			l.cancel()
+		}
+	}()
+}
+
+// Serve runs srv.ListenAndServe as a task and shuts the server down gracefully
+// during shutdown. Use Go and OnShutdown for servers that need TLS or a custom
+// listener.
+func (l *Lifecycle) Serve(name string, srv *http.Server) {
+	l.mu.Lock()
+	l.servers = append(l.servers, namedServer{name: name, srv: srv})
+	l.mu.Unlock()
+
+	l.Go(name, func(ctx context.Context) error {
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+}
+
+// OnShutdown registers a hook that runs after the tasks have finished, or
+// after the deadline. Hooks run in reverse order of registration and receive a
+// context carrying the shutdown deadline.
+func (l *Lifecycle) OnShutdown(name string, fn func(ctx context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, lifecycleHook{name: name, fn: fn})
+}
+
+// AddCloser registers c.Close as a shutdown hook, see OnShutdown.
+func (l *Lifecycle) AddCloser(name string, c io.Closer) {
+	l.OnShutdown(name, func(ctx context.Context) error { return c.Close() })
+}
+
+// Running returns the names of the tasks that have not returned yet, sorted.
+func (l *Lifecycle) Running() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	names := make([]string, 0, len(l.running))
+	for _, name := range l.running {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Wait blocks until a shutdown signal arrives, the parent context is done, a
+// task fails or Shutdown is called, then shuts down and returns the result of
+// Shutdown.
+func (l *Lifecycle) Wait() error {
+	ctx, stop := signal.NotifyContext(l.ctx, l.cfg.Signals...)
+	defer stop()
+
+	<-ctx.Done()
+
+	return l.Shutdown()
+}
+
+// Shutdown runs the shutdown sequence described on Lifecycle and returns a
+// *ShutdownError if anything failed or did not finish in time. Later calls
+// wait for the first one and return the same result.
+func (l *Lifecycle) Shutdown() error {
+	l.once.Do(func() {
+		l.err = l.shutdown()
+	})
+
+	return l.err
+}
+
+func (l *Lifecycle) shutdown() error {
+	l.mu.Lock()
+	l.stopping = true
+	servers := slices.Clone(l.servers)
+	l.mu.Unlock()
+
+	l.cancel()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(l.parent), l.cfg.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+
+	for _, s := range slices.Backward(servers) {
+		if err := s.srv.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("server %s: %w", s.name, err))
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		l.tasks.Wait()
+		close(done)
+	}()
+
+	var unfinished []string
+	select {
+	case <-done:
+	case <-ctx.Done():
+		unfinished = l.Running()
+	}
+
+	l.mu.Lock()
+	errs = append(slices.Clone(l.errs), errs...)
+	hooks := slices.Clone(l.hooks)
+	l.mu.Unlock()
+
+	for _, h := range slices.Backward(hooks) {
+		hctx := WithTaskName(ctx, h.name)
+		_, err := safeCall(hctx, func() (struct{}, error) { return struct{}{}, h.fn(hctx) })
+		if err != nil {
+			errs = append(errs, fmt.Errorf("hook %s: %w", h.name, err))
+		}
+	}
+
+	if len(errs) == 0 && len(unfinished) == 0 {
+		return nil
+	}
+
+	return &ShutdownError{Unfinished: unfinished, Errors: errs}
+}
@@ -0,0 +1,303 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+func TestAsyncUtil_Lifecycle_Shutdown(t *testing.T) {
+	lc, ctx := NewLifecycle(context.Background(), LifecycleConfig{ShutdownTimeout: time.Second})
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(step string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, step)
+	}
+
+	lc.Go("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		record("worker stopped")
+		return ctx.Err()
+	})
+	lc.AddCloser("db", closerFunc(func() error {
+		record("db closed")
+		return nil
+	}))
+	lc.OnShutdown("cache", func(ctx context.Context) error {
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline)
+		record("cache flushed")
+		return nil
+	})
+
+	assert.Equal(t, []string{"worker"}, lc.Running())
+
+	require.NoError(t, lc.Shutdown())
+	assert.Error(t, ctx.Err())
+	assert.Equal(t, []string{"worker stopped", "cache flushed", "db closed"}, order)
+	assert.Empty(t, lc.Running())
+
+	// Later calls return the same result without running the hooks again.
+	require.NoError(t, lc.Shutdown())
+	assert.Len(t, order, 3)
+
+	lc.Go("late", func(ctx context.Context) error {
+		t.Error("task started after shutdown")
+		return nil
+	})
+}
+
+func TestAsyncUtil_Lifecycle_Errors(t *testing.T) {
+	errWorker := errors.New("worker failed")
+	errClose := errors.New("close failed")
+
+	tests := []struct {
+		name          string
+		setup         func(lc *Lifecycle, release chan struct{})
+		expectedErrs  []error
+		unfinished    []string
+		expectedPanic any
+	}{
+		{
+			name: "failing task starts the shutdown",
+			setup: func(lc *Lifecycle, release chan struct{}) {
+				lc.Go("worker", func(ctx context.Context) error { return errWorker })
+				lc.Go("idle", func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				})
+			},
+			expectedErrs: []error{errWorker},
+		},
+		{
+			name: "panicking task starts the shutdown",
+			setup: func(lc *Lifecycle, release chan struct{}) {
+				lc.Go("worker", func(ctx context.Context) error { panic("worker boom") })
+			},
+			expectedPanic: "worker boom",
+		},
+		{
+			name: "hook error",
+			setup: func(lc *Lifecycle, release chan struct{}) {
+				lc.AddCloser("db", closerFunc(func() error { return errClose }))
+				lc.Go("worker", func(ctx context.Context) error { return errWorker })
+			},
+			expectedErrs: []error{errWorker, errClose},
+		},
+		{
+			name: "task ignoring cancellation",
+			setup: func(lc *Lifecycle, release chan struct{}) {
+				lc.Go("stuck", func(ctx context.Context) error {
+					<-release
+					return nil
+				})
+				lc.Go("worker", func(ctx context.Context) error { return errWorker })
+			},
+			expectedErrs: []error{errWorker, context.DeadlineExceeded},
+			unfinished:   []string{"stuck"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			defer close(release)
+
+			lc, _ := NewLifecycle(context.Background(), LifecycleConfig{ShutdownTimeout: 20 * time.Millisecond})
+			tt.setup(lc, release)
+
+			err := lc.Wait()
+
+			var shutdownErr *ShutdownError
+			require.ErrorAs(t, err, &shutdownErr)
+			assert.Equal(t, tt.unfinished, shutdownErr.Unfinished)
+
+			for _, expected := range tt.expectedErrs {
+				assert.ErrorIs(t, err, expected)
+			}
+			if tt.expectedPanic != nil {
+				var perr *PanicError
+				require.ErrorAs(t, err, &perr)
+				assert.Equal(t, tt.expectedPanic, perr.Value)
+				assert.Equal(t, "worker", perr.Task)
+			}
+		})
+	}
+}
+
+func TestAsyncUtil_Lifecycle_ParentCancelled(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	lc, _ := NewLifecycle(parent, LifecycleConfig{})
+
+	lc.Go("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	cancel()
+	assert.NoError(t, lc.Wait())
+}
+
+func TestAsyncUtil_Lifecycle_Signal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals cannot be sent to the current process on windows")
+	}
+
+	// Catch SIGHUP for the whole test, so a signal sent before Wait registers
+	// for it is dropped instead of killing the test binary.
+	caught := make(chan os.Signal, 1)
+	signal.Notify(caught, syscall.SIGHUP)
+	t.Cleanup(func() { signal.Stop(caught) })
+
+	lc, _ := NewLifecycle(context.Background(), LifecycleConfig{Signals: []os.Signal{syscall.SIGHUP}})
+
+	started := make(chan struct{})
+	lc.Go("worker", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return nil
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- lc.Wait() }()
+
+	<-started
+	self, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+
+	// Wait registers for the signal in its own goroutine, so keep sending
+	// until it has; earlier signals only reach caught.
+	assert.Eventually(t, func() bool {
+		self.Signal(syscall.SIGHUP)
+		select {
+		case err := <-done:
+			return assert.NoError(t, err)
+		case <-time.After(10 * time.Millisecond):
+			return false
+		}
+	}, time.Second, time.Millisecond)
+}
+
+func TestAsyncUtil_Lifecycle_Serve(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	inFlight := make(chan struct{})
+	release := make(chan struct{})
+	srv := &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(inFlight)
+		<-release
+		io.WriteString(w, "done")
+	})}
+
+	lc, _ := NewLifecycle(context.Background(), LifecycleConfig{ShutdownTimeout: time.Second})
+	lc.Serve("api", srv)
+
+	type response struct {
+		body string
+		err  error
+	}
+	responses := make(chan response, 1)
+	go func() {
+		var res *http.Response
+		var err error
+		for range 100 {
+			if res, err = http.Get("http://" + addr); err == nil {
+				break
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		if err != nil {
+			responses <- response{err: err}
+			return
+		}
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		responses <- response{body: string(body), err: err}
+	}()
+
+	<-inFlight
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- lc.Shutdown() }()
+
+	// The in-flight request finishes before the server stops.
+	close(release)
+	res := <-responses
+	require.NoError(t, res.err)
+	assert.Equal(t, "done", res.body)
+	assert.NoError(t, <-shutdown)
+	assert.Empty(t, lc.Running())
+}
+
+func TestAsyncUtil_Lifecycle_ServeError(t *testing.T) {
+	lc, _ := NewLifecycle(context.Background(), LifecycleConfig{ShutdownTimeout: time.Second})
+	lc.Serve("api", &http.Server{Addr: "127.0.0.1:-1"})
+
+	err := lc.Wait()
+	assert.ErrorContains(t, err, "task api:")
+}
+
+func TestAsyncUtil_ShutdownError(t *testing.T) {
+	errClose := errors.New("close failed")
+
+	tests := []struct {
+		name     string
+		err      *ShutdownError
+		expected string
+	}{
+		{
+			name:     "unfinished tasks",
+			err:      &ShutdownError{Unfinished: []string{"a", "b"}},
+			expected: "shutdown: tasks did not finish in time: a, b",
+		},
+		{
+			name:     "errors",
+			err:      &ShutdownError{Errors: []error{errClose}},
+			expected: "shutdown: close failed",
+		},
+		{
+			name:     "both",
+			err:      &ShutdownError{Unfinished: []string{"a"}, Errors: []error{errClose}},
+			expected: "shutdown: tasks did not finish in time: a; close failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, tt.err, tt.expected)
+			assert.Equal(t, len(tt.err.Unfinished) > 0, errors.Is(tt.err, context.DeadlineExceeded))
+		})
+	}
+}
+
+func BenchmarkAsyncUtil_Lifecycle_Go(b *testing.B) {
+	lc, _ := NewLifecycle(context.Background(), LifecycleConfig{})
+	defer lc.Shutdown()
+
+	for b.Loop() {
+		lc.Go("task", func(ctx context.Context) error { return nil })
+	}
+}
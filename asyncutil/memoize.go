@@ -0,0 +1,197 @@
+package asyncutil
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoizeConfig configures a Memoizer.
+type MemoizeConfig struct {
+	// TTL is how long a loaded value is fresh. Defaults to 1 minute.
+	TTL time.Duration
+
+	// StaleTTL is how long after TTL an expired value is still served while it
+	// is refreshed in the background (stale-while-revalidate). 0 disables it:
+	// expired values are reloaded before Get returns.
+	StaleTTL time.Duration
+
+	// OnRefreshError is called when a background refresh fails. The stale value
+	// is kept until StaleTTL runs out.
+	OnRefreshError func(err error)
+
+	// Clock is used for expiry. Defaults to RealClock.
+	Clock Clock
+}
+
+// Memoizer caches the results of a loader function per key for a TTL.
+// Concurrent loads of the same key are deduplicated with SingleFlight, and
+// errors are not cached.
+//
+// Example:
+//
+//	profiles := NewMemoizer(func(ctx context.Context, id string) (Profile, error) {
+//	    return client.FetchProfile(ctx, id)
+//	}, MemoizeConfig{TTL: 5 * time.Minute, StaleTTL: time.Minute})
+//
+//	profile, err := profiles.Get(ctx, id)
+type Memoizer[K comparable, V any] struct {
+	load   func(ctx context.Context, key K) (V, error)
+	cfg    MemoizeConfig
+	clock  Clock
+	flight SingleFlight[K, V]
+
+	mu        sync.Mutex
+	entries   map[K]*memoEntry[V]
+	loads     map[K]*memoLoad
+	lastSweep time.Time
+}
+
+type memoEntry[V any] struct {
+	val        V
+	expiresAt  time.Time
+	refreshing bool
+}
+
+// memoLoad tracks the loads in flight for a key. Invalidate bumps gen, so loads
+// started before it know their value is outdated.
+type memoLoad struct {
+	gen   uint64
+	count int
+}
+
+// NewMemoizer returns a Memoizer that loads missing or expired keys with load.
+func NewMemoizer[K comparable, V any](load func(ctx context.Context, key K) (V, error), cfg MemoizeConfig) *Memoizer[K, V] {
+	if cfg.TTL <= 0 {
+		cfg.TTL = time.Minute
+	}
+	cfg.StaleTTL = max(cfg.StaleTTL, 0)
+
+	clock := clockOrReal(cfg.Clock)
+
+	return &Memoizer[K, V]{
+		load:      load,
+		cfg:       cfg,
+		clock:     clock,
+		entries:   make(map[K]*memoEntry[V]),
+		loads:     make(map[K]*memoLoad),
+		lastSweep: clock.Now(),
+	}
+}
+
+// Get returns the cached value for key. A fresh value is returned as is. A
+// stale value is returned immediately while a background refresh runs through
+// SafeGo. Otherwise the value is loaded, sharing the load with concurrent
+// callers for the same key.
+func (m *Memoizer[K, V]) Get(ctx context.Context, key K) (V, error) {
+	m.mu.Lock()
+	now := m.clock.Now()
+	m.sweep(now)
+
+	if entry, ok := m.entries[key]; ok {
+		if now.Before(entry.expiresAt) {
+			m.mu.Unlock()
+			return entry.val, nil
+		}
+
+		if now.Before(entry.expiresAt.Add(m.cfg.StaleTTL)) {
+			if !entry.refreshing {
+				entry.refreshing = true
+				m.refresh(ctx, key)
+			}
+			m.mu.Unlock()
+			return entry.val, nil
+		}
+	}
+	m.mu.Unlock()
+
+	return m.fetch(ctx, key)
+}
+
+// Invalidate removes key, so the next Get loads it again. A load already in
+// flight for key still returns its result to its callers, but does not cache it.
+func (m *Memoizer[K, V]) Invalidate(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	if load, ok := m.loads[key]; ok {
+		load.gen++
+	}
+	m.flight.Forget(key)
+}
+
+// Len returns the number of cached keys, including stale ones.
+func (m *Memoizer[K, V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.entries)
+}
+
+// refresh reloads key in the background. m.mu must be held.
+func (m *Memoizer[K, V]) refresh(ctx context.Context, key K) {
+	ctx = context.WithoutCancel(ctx)
+
+	SafeGo(func() (V, error) {
+		val, err := m.fetch(ctx, key)
+		if err != nil {
+			m.mu.Lock()
+			if entry, ok := m.entries[key]; ok {
+				entry.refreshing = false
+			}
+			m.mu.Unlock()
+
+			if m.cfg.OnRefreshError != nil {
+				m.cfg.OnRefreshError(err)
+			}
+		}
+		return val, err
+	})
+}
+
+// fetch loads key through the single flight and stores the value on success,
+// unless key was invalidated while it loaded.
+func (m *Memoizer[K, V]) fetch(ctx context.Context, key K) (V, error) {
+	val, err, _ := m.flight.DoCtx(ctx, key, func(ctx context.Context) (V, error) {
+		m.mu.Lock()
+		load, ok := m.loads[key]
+		if !ok {
+			load = &memoLoad{}
+			m.loads[key] = load
+		}
+		load.count++
+		gen := load.gen
+		m.mu.Unlock()
+
+		val, err := m.load(ctx, key)
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if load.count--; load.count == 0 {
+			delete(m.loads, key)
+		}
+		if err == nil && load.gen == gen {
+			m.entries[key] = &memoEntry[V]{val: val, expiresAt: m.clock.Now().Add(m.cfg.TTL)}
+		}
+
+		return val, err
+	})
+
+	return val, err
+}
+
+// sweep drops entries past their stale period, at most once per TTL. m.mu must be held.
+func (m *Memoizer[K, V]) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < m.cfg.TTL {
+		return
+	}
+
+	for key, entry := range m.entries {
+		if !now.Before(entry.expiresAt.Add(m.cfg.StaleTTL)) && !entry.refreshing {
+			delete(m.entries, key)
+		}
+	}
+	m.lastSweep = now
+}
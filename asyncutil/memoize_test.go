@@ -0,0 +1,229 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingLoader returns "<key>#<n>" where n counts the loads so far.
+func countingLoader(calls *atomic.Int32) func(ctx context.Context, key string) (string, error) {
+	return func(ctx context.Context, key string) (string, error) {
+		return fmt.Sprintf("%s#%d", key, calls.Add(1)), nil
+	}
+}
+
+func TestAsyncUtil_Memoizer_Get(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      MemoizeConfig
+		advance  time.Duration
+		expected string
+	}{
+		{
+			name:     "fresh value is cached",
+			cfg:      MemoizeConfig{TTL: time.Minute},
+			advance:  30 * time.Second,
+			expected: "user#1",
+		},
+		{
+			name:     "expired value is reloaded",
+			cfg:      MemoizeConfig{TTL: time.Minute},
+			advance:  time.Minute,
+			expected: "user#2",
+		},
+		{
+			name:     "past the stale period is reloaded",
+			cfg:      MemoizeConfig{TTL: time.Minute, StaleTTL: time.Minute},
+			advance:  2 * time.Minute,
+			expected: "user#2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newTestClock()
+			tt.cfg.Clock = clock
+
+			var calls atomic.Int32
+			m := NewMemoizer(countingLoader(&calls), tt.cfg)
+
+			first, err := m.Get(context.Background(), "user")
+			require.NoError(t, err)
+			assert.Equal(t, "user#1", first)
+
+			clock.Advance(tt.advance)
+
+			val, err := m.Get(context.Background(), "user")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, val)
+		})
+	}
+}
+
+func TestAsyncUtil_Memoizer_StaleWhileRevalidate(t *testing.T) {
+	clock := newTestClock()
+
+	var calls atomic.Int32
+	m := NewMemoizer(countingLoader(&calls), MemoizeConfig{TTL: time.Minute, StaleTTL: time.Minute, Clock: clock})
+
+	_, err := m.Get(context.Background(), "user")
+	require.NoError(t, err)
+
+	clock.Advance(90 * time.Second)
+
+	// The stale value is served right away; a single refresh runs in the background.
+	for range 5 {
+		val, err := m.Get(context.Background(), "user")
+		require.NoError(t, err)
+		assert.Contains(t, []string{"user#1", "user#2"}, val)
+	}
+
+	assert.Eventually(t, func() bool {
+		val, _ := m.Get(context.Background(), "user")
+		return val == "user#2"
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestAsyncUtil_Memoizer_RefreshError(t *testing.T) {
+	clock := newTestClock()
+	errDown := errors.New("profile service down")
+
+	var fail atomic.Bool
+	refreshErrs := make(chan error, 1)
+
+	m := NewMemoizer(func(ctx context.Context, key string) (string, error) {
+		if fail.Load() {
+			return "", errDown
+		}
+		return "cached", nil
+	}, MemoizeConfig{
+		TTL:            time.Minute,
+		StaleTTL:       time.Minute,
+		Clock:          clock,
+		OnRefreshError: func(err error) { refreshErrs <- err },
+	})
+
+	_, err := m.Get(context.Background(), "k")
+	require.NoError(t, err)
+
+	fail.Store(true)
+	clock.Advance(90 * time.Second)
+
+	val, err := m.Get(context.Background(), "k")
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", val)
+	assert.ErrorIs(t, <-refreshErrs, errDown)
+
+	// Errors are not cached: once the stale period is over the error surfaces.
+	clock.Advance(time.Minute)
+	_, err = m.Get(context.Background(), "k")
+	assert.ErrorIs(t, err, errDown)
+}
+
+func TestAsyncUtil_Memoizer_DedupesLoads(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	m := NewMemoizer(func(ctx context.Context, key string) (int, error) {
+		calls.Add(1)
+		<-release
+		return 1, nil
+	}, MemoizeConfig{Clock: newTestClock()})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := m.Get(context.Background(), "hot")
+			assert.NoError(t, err)
+			assert.Equal(t, 1, val)
+		}()
+	}
+
+	assert.Eventually(t, func() bool {
+		m.flight.mu.Lock()
+		defer m.flight.mu.Unlock()
+		c, ok := m.flight.calls["hot"]
+		return ok && c.dups == 9
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestAsyncUtil_Memoizer_InvalidateAndSweep(t *testing.T) {
+	clock := newTestClock()
+
+	var calls atomic.Int32
+	m := NewMemoizer(countingLoader(&calls), MemoizeConfig{TTL: time.Minute, Clock: clock})
+
+	m.Get(context.Background(), "a")
+	m.Get(context.Background(), "b")
+	assert.Equal(t, 2, m.Len())
+
+	m.Invalidate("a")
+	assert.Equal(t, 1, m.Len())
+
+	val, _ := m.Get(context.Background(), "a")
+	assert.Equal(t, "a#3", val)
+
+	// "b" expired and is swept on the next access.
+	clock.Advance(2 * time.Minute)
+	m.Get(context.Background(), "c")
+	assert.Equal(t, 1, m.Len())
+}
+
+func TestAsyncUtil_Memoizer_InvalidateDuringLoad(t *testing.T) {
+	var calls atomic.Int32
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	m := NewMemoizer(func(ctx context.Context, key string) (string, error) {
+		n := calls.Add(1)
+		if n == 1 {
+			started <- struct{}{}
+			<-release
+		}
+		return fmt.Sprintf("%s#%d", key, n), nil
+	}, MemoizeConfig{TTL: time.Minute, Clock: newTestClock()})
+
+	done := make(chan string)
+	go func() {
+		val, _ := m.Get(context.Background(), "user")
+		done <- val
+	}()
+
+	// The database is written and the key invalidated while the old value loads.
+	<-started
+	m.Invalidate("user")
+	close(release)
+
+	assert.Equal(t, "user#1", <-done, "callers of the old load still get its value")
+	assert.Equal(t, 0, m.Len(), "the old value is not cached")
+
+	val, err := m.Get(context.Background(), "user")
+	require.NoError(t, err)
+	assert.Equal(t, "user#2", val)
+	assert.Empty(t, m.loads)
+}
+
+func BenchmarkAsyncUtil_Memoizer_Get(b *testing.B) {
+	var calls atomic.Int32
+	m := NewMemoizer(countingLoader(&calls), MemoizeConfig{TTL: time.Hour})
+	ctx := context.Background()
+
+	for b.Loop() {
+		m.Get(ctx, "user")
+	}
+}
@@ -0,0 +1,218 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TaskOutcome tells how a task ended.
+type TaskOutcome int
+
+const (
+	// TaskSucceeded means the task returned a nil error.
+	TaskSucceeded TaskOutcome = iota
+
+	// TaskFailed means the task returned an error.
+	TaskFailed
+
+	// TaskPanicked means the task panicked, or returned the *PanicError of a
+	// panic recovered inside it, e.g. by ParallelMap.
+	TaskPanicked
+)
+
+func (o TaskOutcome) String() string {
+	switch o {
+	case TaskSucceeded:
+		return "succeeded"
+	case TaskFailed:
+		return "failed"
+	case TaskPanicked:
+		return "panicked"
+	default:
+		return fmt.Sprintf("TaskOutcome(%d)", int(o))
+	}
+}
+
+// Metrics receives measurements of the tasks run by SafeGo, SafeGoCtx, Group,
+// ParallelMap, Pool, Scheduler and Lifecycle.
+//
+// task is the name set with WithTaskName, or empty. Implementations are called
+// from the task goroutines and must be safe for concurrent use.
+type Metrics interface {
+	// TaskStarted is called right before a task runs.
+	TaskStarted(task string)
+
+	// TaskFinished is called when a task returns or panics.
+	TaskFinished(task string, outcome TaskOutcome, duration time.Duration)
+
+	// QueueDepth is called with the number of tasks waiting in a Pool every
+	// time it changes.
+	QueueDepth(pool string, depth int)
+}
+
+// TaskMetrics is a global Metrics implementation that receives task
+// measurements. It is nil by default, which disables metrics. Set it once at
+// startup, like OnPanic.
+//
+// The methods map onto Prometheus or OpenTelemetry instruments:
+//
+//	type promMetrics struct {
+//	    started  *prometheus.CounterVec   // labels: task
+//	    finished *prometheus.CounterVec   // labels: task, outcome
+//	    inFlight *prometheus.GaugeVec     // labels: task
+//	    duration *prometheus.HistogramVec // labels: task, outcome
+//	    queue    *prometheus.GaugeVec     // labels: pool
+//	}
+//
+//	func (m *promMetrics) TaskStarted(task string) {
+//	    m.started.WithLabelValues(task).Inc()
+//	    m.inFlight.WithLabelValues(task).Inc()
+//	}
+//
+//	func (m *promMetrics) TaskFinished(task string, outcome TaskOutcome, d time.Duration) {
+//	    m.finished.WithLabelValues(task, outcome.String()).Inc()
+//	    m.inFlight.WithLabelValues(task).Dec()
+//	    m.duration.WithLabelValues(task, outcome.String()).Observe(d.Seconds())
+//	}
+//
+//	func (m *promMetrics) QueueDepth(pool string, depth int) {
+//	    m.queue.WithLabelValues(pool).Set(float64(depth))
+//	}
+//
+//	TaskMetrics = newPromMetrics(prometheus.DefaultRegisterer)
+var TaskMetrics Metrics
+
+// runTask is safeCall for the task entry points, reporting to TaskMetrics.
+func runTask[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	m := TaskMetrics
+	if m == nil {
+		return safeCall(ctx, fn)
+	}
+
+	task := TaskName(ctx)
+	m.TaskStarted(task)
+	start := time.Now()
+
+	val, err := safeCall(ctx, fn)
+
+	var perr *PanicError
+	outcome := TaskSucceeded
+	switch {
+	case errors.As(err, &perr):
+		outcome = TaskPanicked
+	case err != nil:
+		outcome = TaskFailed
+	}
+	m.TaskFinished(task, outcome, time.Since(start))
+
+	return val, err
+}
+
+func reportQueueDepth(pool string, depth int) {
+	if m := TaskMetrics; m != nil {
+		m.QueueDepth(pool, depth)
+	}
+}
+
+// MemoryMetrics is a Metrics implementation that keeps everything in memory,
+// meant for tests and debug endpoints. The zero value is ready to use.
+//
+// Example:
+//
+//	metrics := &MemoryMetrics{}
+//	TaskMetrics = metrics
+//	defer func() { TaskMetrics = nil }()
+//
+//	runImport(ctx)
+//
+//	assert.Equal(t, 0, metrics.Finished("import", TaskPanicked))
+type MemoryMetrics struct {
+	mu        sync.Mutex
+	started   map[string]int
+	finished  map[string]map[TaskOutcome]int
+	durations map[string][]time.Duration
+	queues    map[string]int
+}
+
+func (m *MemoryMetrics) TaskStarted(task string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.started == nil {
+		m.started = make(map[string]int)
+	}
+	m.started[task]++
+}
+
+func (m *MemoryMetrics) TaskFinished(task string, outcome TaskOutcome, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.finished == nil {
+		m.finished = make(map[string]map[TaskOutcome]int)
+		m.durations = make(map[string][]time.Duration)
+	}
+	if m.finished[task] == nil {
+		m.finished[task] = make(map[TaskOutcome]int)
+	}
+	m.finished[task][outcome]++
+	m.durations[task] = append(m.durations[task], duration)
+}
+
+func (m *MemoryMetrics) QueueDepth(pool string, depth int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.queues == nil {
+		m.queues = make(map[string]int)
+	}
+	m.queues[pool] = depth
+}
+
+// Started returns how many times task has started.
+func (m *MemoryMetrics) Started(task string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.started[task]
+}
+
+// Finished returns how many runs of task ended with outcome.
+func (m *MemoryMetrics) Finished(task string, outcome TaskOutcome) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.finished[task][outcome]
+}
+
+// InFlight returns how many runs of task have started but not finished.
+func (m *MemoryMetrics) InFlight(task string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := m.started[task]
+	for _, count := range m.finished[task] {
+		n -= count
+	}
+	return n
+}
+
+// Durations returns the durations of the finished runs of task, in the order
+// they finished.
+func (m *MemoryMetrics) Durations(task string) []time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]time.Duration(nil), m.durations[task]...)
+}
+
+// QueueLen returns the last queue depth reported for pool.
+func (m *MemoryMetrics) QueueLen(pool string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.queues[pool]
+}
@@ -0,0 +1,151 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useMetrics installs a fresh MemoryMetrics as TaskMetrics for the test.
+func useMetrics(t testing.TB) *MemoryMetrics {
+	metrics := &MemoryMetrics{}
+	TaskMetrics = metrics
+	t.Cleanup(func() { TaskMetrics = nil })
+
+	return metrics
+}
+
+func TestAsyncUtil_TaskMetrics_SafeGoCtx(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name     string
+		fn       func(ctx context.Context) (int, error)
+		expected TaskOutcome
+	}{
+		{name: "success", fn: func(ctx context.Context) (int, error) { return 1, nil }, expected: TaskSucceeded},
+		{name: "failure", fn: func(ctx context.Context) (int, error) { return 0, errFailed }, expected: TaskFailed},
+		{name: "panic", fn: func(ctx context.Context) (int, error) { panic("boom") }, expected: TaskPanicked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := useMetrics(t)
+			ctx := WithTaskName(context.Background(), "load-user")
+
+			Await(ctx, SafeGoCtx(ctx, tt.fn))
+
+			assert.Equal(t, 1, metrics.Started("load-user"))
+			assert.Equal(t, 1, metrics.Finished("load-user", tt.expected))
+			assert.Equal(t, 0, metrics.InFlight("load-user"))
+			assert.Len(t, metrics.Durations("load-user"), 1)
+		})
+	}
+}
+
+func TestAsyncUtil_TaskMetrics_InFlight(t *testing.T) {
+	metrics := useMetrics(t)
+
+	release := make(chan struct{})
+	ch := SafeGo(func() (int, error) {
+		<-release
+		return 1, nil
+	})
+
+	assert.Eventually(t, func() bool { return metrics.InFlight("") == 1 }, time.Second, time.Millisecond)
+
+	close(release)
+	<-ch
+	assert.Equal(t, 0, metrics.InFlight(""))
+	assert.Equal(t, 1, metrics.Finished("", TaskSucceeded))
+}
+
+func TestAsyncUtil_TaskMetrics_ParallelMap(t *testing.T) {
+	metrics := useMetrics(t)
+	ctx := WithTaskName(context.Background(), "resize")
+
+	ParallelMap(ctx, []int{1, 2, 3}, 1, func(ctx context.Context, n int, _ int) (int, error) {
+		if n == 2 {
+			panic("bad image")
+		}
+		return n, nil
+	})
+
+	// The panic is recovered per item but still counted as a panic.
+	assert.Equal(t, 1, metrics.Finished("resize", TaskPanicked))
+	assert.Equal(t, 1, metrics.Finished("resize", TaskSucceeded))
+	assert.Equal(t, 0, metrics.InFlight("resize"))
+}
+
+func TestAsyncUtil_TaskMetrics_PoolQueueDepth(t *testing.T) {
+	metrics := useMetrics(t)
+
+	pool := NewPool[int](1, 5)
+	pool.SetName("thumbnails")
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	first, err := pool.Submit(context.Background(), func(ctx context.Context) (int, error) {
+		close(started)
+		<-release
+		return 0, nil
+	})
+	require.NoError(t, err)
+	<-started
+
+	var queued []<-chan Result[int]
+	for range 3 {
+		ch, err := pool.TrySubmit(context.Background(), func(ctx context.Context) (int, error) { return 0, nil })
+		require.NoError(t, err)
+		queued = append(queued, ch)
+	}
+	assert.Equal(t, 3, metrics.QueueLen("thumbnails"))
+
+	close(release)
+	<-first
+	for _, ch := range queued {
+		<-ch
+	}
+	require.NoError(t, pool.Shutdown(context.Background()))
+
+	assert.Equal(t, 0, metrics.QueueLen("thumbnails"))
+	assert.Equal(t, 4, metrics.Finished("", TaskSucceeded))
+}
+
+func TestAsyncUtil_TaskMetrics_Disabled(t *testing.T) {
+	TaskMetrics = nil
+
+	val, err := Await(context.Background(), SafeGo(func() (int, error) { return 42, nil }))
+	assert.NoError(t, err)
+	assert.Equal(t, 42, val)
+}
+
+func TestAsyncUtil_TaskOutcome_String(t *testing.T) {
+	tests := []struct {
+		outcome  TaskOutcome
+		expected string
+	}{
+		{TaskSucceeded, "succeeded"},
+		{TaskFailed, "failed"},
+		{TaskPanicked, "panicked"},
+		{TaskOutcome(9), "TaskOutcome(9)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.outcome.String())
+		})
+	}
+}
+
+func BenchmarkAsyncUtil_TaskMetrics_SafeGo(b *testing.B) {
+	useMetrics(b)
+
+	for b.Loop() {
+		<-SafeGo(func() (int, error) { return 1, nil })
+	}
+}
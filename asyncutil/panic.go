@@ -0,0 +1,181 @@
+package asyncutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+)
+
+// PanicError is the error returned when a task panics. Use errors.As to tell
+// a panic apart from a regular error and to inspect the recovered value.
+//
+// Example:
+//
+//	res := <-SafeGo(fn)
+//
+//	var perr *PanicError
+//	if errors.As(res.Err, &perr) {
+//	    log.Printf("task %q panicked with %v at %s", perr.Task, perr.Value, perr.Stack[0])
+//	}
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+
+	// Task is the name set on the task context with WithTaskName, if any.
+	Task string
+
+	// Labels are the pprof labels found on the task context, if any.
+	Labels map[string]string
+
+	// Goroutine is the ID of the goroutine that panicked.
+	Goroutine int
+
+	// Stack lists the frames from the panicking function outwards.
+	Stack []StackFrame
+}
+
+// StackFrame is a single frame of a panic stack trace.
+type StackFrame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+func (f StackFrame) String() string {
+	return fmt.Sprintf("%s (%s:%d)", f.Function, f.File, f.Line)
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic recovered: %v", e.Value)
+}
+
+// Unwrap returns the panic value when it is an error, so errors.Is can match
+// errors passed to panic.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// StackTrace formats Stack in the layout of runtime/debug.Stack.
+func (e *PanicError) StackTrace() string {
+	var sb strings.Builder
+	for _, f := range e.Stack {
+		fmt.Fprintf(&sb, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+	}
+	return sb.String()
+}
+
+// PanicHandler receives recovered panics together with the context of the task
+// that panicked.
+type PanicHandler func(ctx context.Context, err *PanicError)
+
+// OnPanicCtx is a global handler called whenever a panic is recovered, after
+// OnPanic. Unlike OnPanic it receives the task context, for example to log
+// through SlogPanicHandler with request-scoped attributes.
+var OnPanicCtx PanicHandler
+
+// SlogPanicHandler returns a PanicHandler that logs panics with logger at error
+// level, including the task name, labels, goroutine and stack. A nil logger
+// uses slog.Default().
+//
+// Example:
+//
+//	asyncutil.OnPanicCtx = asyncutil.SlogPanicHandler(logger)
+func SlogPanicHandler(logger *slog.Logger) PanicHandler {
+	return func(ctx context.Context, err *PanicError) {
+		l := logger
+		if l == nil {
+			l = slog.Default()
+		}
+
+		l.ErrorContext(ctx, "panic recovered",
+			slog.String("task", err.Task),
+			slog.Any("panic", err.Value),
+			slog.Any("labels", err.Labels),
+			slog.Int("goroutine", err.Goroutine),
+			slog.String("stack", err.StackTrace()),
+		)
+	}
+}
+
+type taskNameKey struct{}
+
+// WithTaskName names the task run with ctx. The name is reported in PanicError.Task.
+//
+// Example:
+//
+//	ctx = WithTaskName(ctx, "send-receipts")
+//	err := ParallelForEach(ctx, orders, 4, sendReceipt)
+func WithTaskName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, taskNameKey{}, name)
+}
+
+// TaskName returns the name set with WithTaskName, or "" if there is none.
+func TaskName(ctx context.Context) string {
+	name, _ := ctx.Value(taskNameKey{}).(string)
+	return name
+}
+
+// newPanicError builds a PanicError for value. It must be called from the
+// deferred function that recovered the panic.
+func newPanicError(ctx context.Context, value any) *PanicError {
+	var labels map[string]string
+	pprof.ForLabels(ctx, func(key, val string) bool {
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[key] = val
+		return true
+	})
+
+	return &PanicError{
+		Value:     value,
+		Task:      TaskName(ctx),
+		Labels:    labels,
+		Goroutine: goroutineID(),
+		Stack:     panicStack(),
+	}
+}
+
+// panicStack returns the frames above runtime.gopanic, i.e. starting at the
+// function that panicked.
+func panicStack() []StackFrame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var stack []StackFrame
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, StackFrame{Function: frame.Function, File: frame.File, Line: frame.Line})
+
+		if frame.Function == "runtime.gopanic" {
+			stack = stack[:0]
+		}
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
+
+// goroutineID parses the ID from the "goroutine N [status]:" header of the
+// current stack trace.
+func goroutineID() int {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i > 0 {
+		buf = buf[:i]
+	}
+
+	id, _ := strconv.Atoi(string(buf))
+	return id
+}
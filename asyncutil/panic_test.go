@@ -0,0 +1,173 @@
+package asyncutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func panickingTask() (int, error) {
+	panic("task boom")
+}
+
+func TestAsyncUtil_PanicError(t *testing.T) {
+	errCause := errors.New("nil pointer in cache")
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		fn            func() (int, error)
+		expectedValue any
+		expectedTask  string
+		expectedLabel map[string]string
+		expectedIs    error
+		expectedMsg   string
+	}{
+		{
+			name:          "string value",
+			ctx:           context.Background(),
+			fn:            panickingTask,
+			expectedValue: "task boom",
+			expectedMsg:   "panic recovered: task boom",
+		},
+		{
+			name:          "error value unwraps",
+			ctx:           context.Background(),
+			fn:            func() (int, error) { panic(errCause) },
+			expectedValue: errCause,
+			expectedIs:    errCause,
+			expectedMsg:   "panic recovered: nil pointer in cache",
+		},
+		{
+			name:          "task name and labels from context",
+			ctx:           pprof.WithLabels(WithTaskName(context.Background(), "sync-users"), pprof.Labels("tenant", "acme")),
+			fn:            panickingTask,
+			expectedValue: "task boom",
+			expectedTask:  "sync-users",
+			expectedLabel: map[string]string{"tenant": "acme"},
+			expectedMsg:   "panic recovered: task boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := <-SafeGoCtx(tt.ctx, func(ctx context.Context) (int, error) {
+				return tt.fn()
+			})
+
+			var perr *PanicError
+			require.ErrorAs(t, res.Err, &perr)
+
+			assert.Equal(t, tt.expectedValue, perr.Value)
+			assert.Equal(t, tt.expectedTask, perr.Task)
+			assert.Equal(t, tt.expectedLabel, perr.Labels)
+			assert.EqualError(t, perr, tt.expectedMsg)
+			assert.Positive(t, perr.Goroutine)
+
+			if tt.expectedIs != nil {
+				assert.ErrorIs(t, res.Err, tt.expectedIs)
+			}
+		})
+	}
+}
+
+func TestAsyncUtil_PanicError_Stack(t *testing.T) {
+	_, err := safeCall(context.Background(), panickingTask)
+
+	var perr *PanicError
+	require.ErrorAs(t, err, &perr)
+	require.NotEmpty(t, perr.Stack)
+
+	top := perr.Stack[0]
+	assert.True(t, strings.HasSuffix(top.Function, "asyncutil.panickingTask"), top.Function)
+	assert.True(t, strings.HasSuffix(top.File, "panic_test.go"), top.File)
+	assert.Positive(t, top.Line)
+
+	assert.True(t, strings.HasPrefix(perr.StackTrace(), top.Function+"\n\t"+top.File))
+	assert.Contains(t, top.String(), "panic_test.go:")
+}
+
+func TestAsyncUtil_OnPanicCtx(t *testing.T) {
+	var mu sync.Mutex
+	var gotTask string
+	var gotErr *PanicError
+
+	OnPanicCtx = func(ctx context.Context, err *PanicError) {
+		mu.Lock()
+		defer mu.Unlock()
+		gotTask = TaskName(ctx)
+		gotErr = err
+	}
+	defer func() { OnPanicCtx = nil }()
+
+	ctx := WithTaskName(context.Background(), "import-csv")
+	_, err := ParallelMap(ctx, []int{1}, 1, func(ctx context.Context, _ int, _ int) (int, error) {
+		panic("bad row")
+	})
+
+	assert.ErrorContains(t, err, "panic recovered: bad row")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "import-csv", gotTask)
+	if assert.NotNil(t, gotErr) {
+		assert.Equal(t, "bad row", gotErr.Value)
+		assert.Equal(t, "import-csv", gotErr.Task)
+	}
+}
+
+func TestAsyncUtil_SlogPanicHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	OnPanicCtx = SlogPanicHandler(logger)
+	defer func() { OnPanicCtx = nil }()
+
+	ctx := pprof.WithLabels(WithTaskName(context.Background(), "reindex"), pprof.Labels("shard", "3"))
+	<-SafeGoCtx(ctx, func(ctx context.Context) (int, error) {
+		panic("index corrupted")
+	})
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+	assert.Equal(t, "ERROR", entry["level"])
+	assert.Equal(t, "panic recovered", entry["msg"])
+	assert.Equal(t, "reindex", entry["task"])
+	assert.Equal(t, "index corrupted", entry["panic"])
+	assert.Equal(t, map[string]any{"shard": "3"}, entry["labels"])
+	assert.Contains(t, entry["stack"], "panic_test.go")
+}
+
+func TestAsyncUtil_PanicHandlerPanics(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer slog.SetDefault(prev)
+
+	OnPanic = func(err error) { panic("handler boom") }
+	defer func() { OnPanic = nil }()
+
+	res := <-SafeGo(panickingTask)
+
+	assert.ErrorContains(t, res.Err, "panic recovered: task boom")
+	assert.Contains(t, buf.String(), "asyncutil: panic in panic handler")
+	assert.Contains(t, buf.String(), "handler boom")
+}
+
+func BenchmarkAsyncUtil_safeCall_Panic(b *testing.B) {
+	ctx := WithTaskName(context.Background(), "bench")
+
+	for b.Loop() {
+		safeCall(ctx, panickingTask)
+	}
+}
@@ -0,0 +1,84 @@
+package asyncutil
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+// IndexError reports the index of the item that failed in ParallelMap or ParallelForEach.
+type IndexError struct {
+	Index int
+	Err   error
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e *IndexError) Unwrap() error {
+	return e.Err
+}
+
+// ParallelMap is the concurrent counterpart of sliceutil.Map. It applies fn to
+// every item using at most `concurrency` goroutines and returns the results in
+// input order.
+//
+// The first failing item cancels the context passed to the other calls, and
+// items that have not started yet are skipped. Panics are recovered per item and
+// reported to OnPanic. Item failures are returned as *IndexError, the first one
+// first, so errors.As reports which index failed.
+//
+// A concurrency <= 0 uses runtime.GOMAXPROCS(0).
+//
+// Example:
+//
+//	users, err := ParallelMap(ctx, ids, 8, func(ctx context.Context, id string, _ int) (User, error) {
+//	    return repo.FindUser(ctx, id)
+//	})
+//
+//	var idxErr *IndexError
+//	if errors.As(err, &idxErr) {
+//	    log.Printf("loading user %s failed: %v", ids[idxErr.Index], idxErr.Err)
+//	}
+func ParallelMap[T any, R any](ctx context.Context, items []T, concurrency int, fn func(ctx context.Context, item T, index int) (R, error)) ([]R, error) {
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	g, _ := NewGroup[R](ctx)
+	g.SetLimit(concurrency)
+
+	for i, item := range items {
+		g.Go(func(ctx context.Context) (R, error) {
+			val, err := safeCall(ctx, func() (R, error) { return fn(ctx, item, i) })
+			if err != nil {
+				return val, &IndexError{Index: i, Err: err}
+			}
+			return val, nil
+		})
+	}
+
+	results, err := g.Wait()
+	if results == nil {
+		results = make([]R, 0)
+	}
+
+	return results, err
+}
+
+// ParallelForEach calls fn for every item using at most `concurrency` goroutines.
+// It stops early and reports failures the same way as ParallelMap.
+//
+// Example:
+//
+//	err := ParallelForEach(ctx, orders, 4, func(ctx context.Context, o Order, _ int) error {
+//	    return mailer.SendReceipt(ctx, o)
+//	})
+func ParallelForEach[T any](ctx context.Context, items []T, concurrency int, fn func(ctx context.Context, item T, index int) error) error {
+	_, err := ParallelMap(ctx, items, concurrency, func(ctx context.Context, item T, index int) (struct{}, error) {
+		return struct{}{}, fn(ctx, item, index)
+	})
+
+	return err
+}
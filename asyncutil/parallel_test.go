@@ -0,0 +1,185 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsyncUtil_ParallelMap(t *testing.T) {
+	errBad := errors.New("bad item")
+
+	tests := []struct {
+		name          string
+		items         []int
+		concurrency   int
+		fn            func(ctx context.Context, item int, index int) (string, error)
+		expected      []string
+		expectedIndex int
+		expectedErr   error
+		errContains   string
+	}{
+		{
+			name:        "preserves input order",
+			items:       []int{5, 1, 3},
+			concurrency: 3,
+			fn: func(ctx context.Context, item int, index int) (string, error) {
+				time.Sleep(time.Duration(item) * time.Millisecond)
+				return fmt.Sprintf("%d-%d", index, item), nil
+			},
+			expected: []string{"0-5", "1-1", "2-3"},
+		},
+		{
+			name:        "default concurrency",
+			items:       []int{1, 2},
+			concurrency: 0,
+			fn: func(ctx context.Context, item int, index int) (string, error) {
+				return fmt.Sprint(item * 2), nil
+			},
+			expected: []string{"2", "4"},
+		},
+		{
+			name:  "empty input",
+			items: []int{},
+			fn: func(ctx context.Context, item int, index int) (string, error) {
+				return "", nil
+			},
+			expected: []string{},
+		},
+		{
+			name:        "reports failed index",
+			items:       []int{1, 2, 3},
+			concurrency: 1,
+			fn: func(ctx context.Context, item int, index int) (string, error) {
+				if item == 2 {
+					return "", errBad
+				}
+				return fmt.Sprint(item), nil
+			},
+			expected:      []string{"1", "", ""},
+			expectedIndex: 1,
+			expectedErr:   errBad,
+		},
+		{
+			name:        "recovers panic per item",
+			items:       []int{1, 2},
+			concurrency: 1,
+			fn: func(ctx context.Context, item int, index int) (string, error) {
+				if item == 1 {
+					panic("map boom")
+				}
+				return fmt.Sprint(item), nil
+			},
+			expected:      []string{"", ""},
+			expectedIndex: 0,
+			errContains:   "panic recovered: map boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := ParallelMap(context.Background(), tt.items, tt.concurrency, tt.fn)
+
+			assert.Equal(t, tt.expected, results)
+
+			if tt.expectedErr == nil && tt.errContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var idxErr *IndexError
+			if assert.ErrorAs(t, err, &idxErr) {
+				assert.Equal(t, tt.expectedIndex, idxErr.Index)
+			}
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+			if tt.errContains != "" {
+				assert.ErrorContains(t, err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestAsyncUtil_ParallelMap_StopsEarly(t *testing.T) {
+	var started atomic.Int32
+
+	items := make([]int, 100)
+	_, err := ParallelMap(context.Background(), items, 2, func(ctx context.Context, _ int, index int) (int, error) {
+		started.Add(1)
+		if index == 0 {
+			return 0, errors.New("fail fast")
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+			return index, nil
+		}
+	})
+
+	assert.EqualError(t, err, "item 0: fail fast")
+	assert.Less(t, started.Load(), int32(100))
+}
+
+func TestAsyncUtil_ParallelMap_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var ran atomic.Bool
+	results, err := ParallelMap(ctx, []int{1, 2, 3}, 2, func(ctx context.Context, item int, _ int) (int, error) {
+		ran.Store(true)
+		return item, nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []int{0, 0, 0}, results)
+	assert.False(t, ran.Load())
+}
+
+func TestAsyncUtil_ParallelForEach(t *testing.T) {
+	t.Run("visits every item", func(t *testing.T) {
+		var sum atomic.Int64
+
+		err := ParallelForEach(context.Background(), []int{1, 2, 3, 4}, 2, func(ctx context.Context, item int, _ int) error {
+			sum.Add(int64(item))
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), sum.Load())
+	})
+
+	t.Run("reports failed index", func(t *testing.T) {
+		err := ParallelForEach(context.Background(), []string{"a", "b"}, 1, func(ctx context.Context, item string, _ int) error {
+			if item == "b" {
+				return errors.New("b is invalid")
+			}
+			return nil
+		})
+
+		var idxErr *IndexError
+		assert.ErrorAs(t, err, &idxErr)
+		assert.Equal(t, 1, idxErr.Index)
+		assert.EqualError(t, idxErr, "item 1: b is invalid")
+	})
+}
+
+func BenchmarkAsyncUtil_ParallelMap(b *testing.B) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	ctx := context.Background()
+	for b.Loop() {
+		ParallelMap(ctx, items, 8, func(ctx context.Context, item int, _ int) (int, error) {
+			return item * 2, nil
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// StageError reports the pipeline stage and the item that failed.
+type StageError struct {
+	// Stage is the name given to Source, Stage or Sink.
+	Stage string
+
+	// Index is the position of the item in the stage input, counting from 0.
+	// It is -1 for Source failures, which are not tied to an item.
+	Index int
+
+	// Item is the input item that failed, nil for Source failures.
+	Item any
+
+	Err error
+}
+
+func (e *StageError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("stage %s: %v", e.Stage, e.Err)
+	}
+	return fmt.Sprintf("stage %s: item %d: %v", e.Stage, e.Index, e.Err)
+}
+
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// Pipeline connects Source, Stage, FanOut, FanIn and Sink through channels.
+// The first stage to fail or panic cancels the pipeline context, so every other
+// stage stops, and Wait returns the failure as a *StageError.
+//
+// Each function takes a buffer size for the channel it returns. A full buffer
+// blocks the stage writing to it, so a slow stage slows down the ones before
+// it instead of piling items up in memory.
+//
+// Example:
+//
+//	p, ctx := NewPipeline(ctx)
+//
+//	rows := Source(p, "read", 100, func(ctx context.Context, emit func(Row) error) error {
+//	    return csvReader.Each(func(row Row) error { return emit(row) })
+//	})
+//	users := Stage(p, "parse", rows, 4, 100, func(ctx context.Context, row Row) (User, error) {
+//	    return parseUser(row)
+//	})
+//	Sink(p, "save", users, 2, func(ctx context.Context, u User) error {
+//	    return repo.SaveUser(ctx, u)
+//	})
+//
+//	if err := p.Wait(); err != nil {
+//	    var stageErr *StageError
+//	    if errors.As(err, &stageErr) {
+//	        log.Printf("import failed at %s item %d (%v): %v", stageErr.Stage, stageErr.Index, stageErr.Item, stageErr.Err)
+//	    }
+//	}
+type Pipeline struct {
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu  sync.Mutex
+	err error
+}
+
+// NewPipeline returns an empty Pipeline and the context shared by its stages.
+// The context is cancelled when a stage fails or when Wait returns.
+func NewPipeline(ctx context.Context) (*Pipeline, context.Context) {
+	pctx, cancel := context.WithCancel(ctx)
+	return &Pipeline{parent: ctx, ctx: pctx, cancel: cancel}, pctx
+}
+
+// Wait blocks until every stage has finished and returns the first failure, or
+// the context error if the parent context was done first.
+//
+// Every output channel must be consumed, by a Sink or by the caller, or Wait
+// blocks forever.
+func (p *Pipeline) Wait() error {
+	p.wg.Wait()
+	p.cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	return p.parent.Err()
+}
+
+func (p *Pipeline) fail(err *StageError) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Stages stopping because of an earlier failure are not failures.
+	if p.ctx.Err() != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		return
+	}
+
+	if p.err == nil {
+		p.err = err
+	}
+	p.cancel()
+}
+
+func (p *Pipeline) goStage(fn func()) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		fn()
+	}()
+}
+
+// send delivers v to out unless the pipeline is cancelled first.
+func send[T any](ctx context.Context, out chan<- T, v T) bool {
+	select {
+	case out <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// receive takes the next item from in. It reports false when in is closed or
+// the pipeline is cancelled.
+func receive[T any](ctx context.Context, in <-chan T) (T, bool) {
+	select {
+	case v, ok := <-in:
+		return v, ok
+	case <-ctx.Done():
+		var zero T
+		return zero, false
+	}
+}
+
+// Source starts a stage that produces items by calling emit. emit blocks while
+// the output buffer is full and returns the context error once the pipeline is
+// cancelled; gen should return when it does.
+//
+// Example:
+//
+//	ids := Source(p, "ids", 50, func(ctx context.Context, emit func(int64) error) error {
+//	    for _, id := range pendingIDs {
+//	        if err := emit(id); err != nil {
+//	            return err
+//	        }
+//	    }
+//	    return nil
+//	})
+func Source[T any](p *Pipeline, name string, buffer int, gen func(ctx context.Context, emit func(T) error) error) <-chan T {
+	out := make(chan T, max(buffer, 0))
+	ctx := WithTaskName(p.ctx, name)
+
+	p.goStage(func() {
+		defer close(out)
+
+		emit := func(v T) error {
+			if !send(ctx, out, v) {
+				return ctx.Err()
+			}
+			return nil
+		}
+
+		_, err := safeCall(ctx, func() (struct{}, error) {
+			return struct{}{}, gen(ctx, emit)
+		})
+		if err != nil {
+			p.fail(&StageError{Stage: name, Index: -1, Err: err})
+		}
+	})
+
+	return out
+}
+
+// Stage starts `workers` goroutines that apply fn to every item of in and send
+// the results to the returned channel. Items keep their order only when
+// workers is 1. A workers <= 0 uses runtime.GOMAXPROCS(0).
+//
+// Example:
+//
+//	enriched := Stage(p, "geocode", users, 8, 100, func(ctx context.Context, u User) (User, error) {
+//	    loc, err := geo.Lookup(ctx, u.Address)
+//	    u.Location = loc
+//	    return u, err
+//	})
+func Stage[In any, Out any](p *Pipeline, name string, in <-chan In, workers int, buffer int, fn func(ctx context.Context, item In) (Out, error)) <-chan Out {
+	out := make(chan Out, max(buffer, 0))
+
+	process := func(ctx context.Context, item In, index int) bool {
+		val, err := safeCall(ctx, func() (Out, error) { return fn(ctx, item) })
+		if err != nil {
+			p.fail(&StageError{Stage: name, Index: index, Item: item, Err: err})
+			return false
+		}
+		return send(ctx, out, val)
+	}
+
+	startWorkers(p, name, in, workers, process, func() { close(out) })
+
+	return out
+}
+
+// Sink starts `workers` goroutines that call fn for every item of in. It ends
+// the pipeline; its failures are reported by Wait. A workers <= 0 uses
+// runtime.GOMAXPROCS(0).
+//
+// Example:
+//
+//	Sink(p, "save", users, 2, func(ctx context.Context, u User) error {
+//	    return repo.SaveUser(ctx, u)
+//	})
+func Sink[T any](p *Pipeline, name string, in <-chan T, workers int, fn func(ctx context.Context, item T) error) {
+	process := func(ctx context.Context, item T, index int) bool {
+		_, err := safeCall(ctx, func() (struct{}, error) { return struct{}{}, fn(ctx, item) })
+		if err != nil {
+			p.fail(&StageError{Stage: name, Index: index, Item: item, Err: err})
+			return false
+		}
+		return true
+	}
+
+	startWorkers(p, name, in, workers, process, func() {})
+}
+
+// startWorkers runs process for every item of in on `workers` goroutines and
+// calls done once they have all returned. Items are numbered in the order they
+// are received.
+func startWorkers[T any](p *Pipeline, name string, in <-chan T, workers int, process func(ctx context.Context, item T, index int) bool, done func()) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	ctx := WithTaskName(p.ctx, name)
+
+	var (
+		mu   sync.Mutex
+		next int
+		wg   sync.WaitGroup
+	)
+
+	wg.Add(workers)
+	for range workers {
+		p.goStage(func() {
+			defer wg.Done()
+
+			for {
+				mu.Lock()
+				item, ok := receive(ctx, in)
+				index := next
+				next++
+				mu.Unlock()
+
+				if !ok || !process(ctx, item, index) {
+					return
+				}
+			}
+		})
+	}
+
+	p.goStage(func() {
+		wg.Wait()
+		done()
+	})
+}
+
+// FanOut copies every item of in to n output channels, e.g. to write the same
+// records to a database and a search index. The slowest consumer sets the pace
+// for all of them.
+//
+// Example:
+//
+//	outs := FanOut(p, users, 2, 100)
+//	Sink(p, "db", outs[0], 4, repo.SaveUser)
+//	Sink(p, "search", outs[1], 1, index.IndexUser)
+func FanOut[T any](p *Pipeline, in <-chan T, n int, buffer int) []<-chan T {
+	outs := make([]chan T, max(n, 0))
+	result := make([]<-chan T, len(outs))
+	for i := range outs {
+		outs[i] = make(chan T, max(buffer, 0))
+		result[i] = outs[i]
+	}
+
+	p.goStage(func() {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+
+		for {
+			item, ok := receive(p.ctx, in)
+			if !ok {
+				return
+			}
+			for _, out := range outs {
+				if !send(p.ctx, out, item) {
+					return
+				}
+			}
+		}
+	})
+
+	return result
+}
+
+// FanIn merges the items of every input channel into one channel, in no
+// particular order. The output is closed once every input is closed.
+//
+// Example:
+//
+//	all := FanIn(p, 100, fromAPI, fromCSV)
+func FanIn[T any](p *Pipeline, buffer int, ins ...<-chan T) <-chan T {
+	out := make(chan T, max(buffer, 0))
+
+	var wg sync.WaitGroup
+	wg.Add(len(ins))
+	for _, in := range ins {
+		p.goStage(func() {
+			defer wg.Done()
+
+			for {
+				item, ok := receive(p.ctx, in)
+				if !ok || !send(p.ctx, out, item) {
+					return
+				}
+			}
+		})
+	}
+
+	p.goStage(func() {
+		wg.Wait()
+		close(out)
+	})
+
+	return out
+}
@@ -0,0 +1,325 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sliceSource emits every item of items.
+func sliceSource[T any](items []T) func(ctx context.Context, emit func(T) error) error {
+	return func(ctx context.Context, emit func(T) error) error {
+		for _, item := range items {
+			if err := emit(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// collect returns a Sink callback that records items and the slice they end up in.
+func collect[T any]() (func(ctx context.Context, item T) error, func() []T) {
+	var (
+		mu    sync.Mutex
+		items []T
+	)
+
+	sink := func(ctx context.Context, item T) error {
+		mu.Lock()
+		defer mu.Unlock()
+		items = append(items, item)
+		return nil
+	}
+	get := func() []T {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(items)
+	}
+
+	return sink, get
+}
+
+// errIf returns err when cond holds.
+func errIf(cond bool, err error) error {
+	if cond {
+		return err
+	}
+	return nil
+}
+
+func TestAsyncUtil_Pipeline(t *testing.T) {
+	tests := []struct {
+		name     string
+		workers  int
+		items    []int
+		expected []string
+		ordered  bool
+	}{
+		{name: "single worker keeps order", workers: 1, items: []int{1, 2, 3, 4}, expected: []string{"2", "4", "6", "8"}, ordered: true},
+		{name: "multiple workers", workers: 4, items: []int{1, 2, 3, 4, 5}, expected: []string{"10", "2", "4", "6", "8"}},
+		{name: "default workers", workers: 0, items: []int{1, 2}, expected: []string{"2", "4"}},
+		{name: "empty source", workers: 2, items: nil, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := NewPipeline(context.Background())
+
+			nums := Source(p, "numbers", 2, sliceSource(tt.items))
+			doubled := Stage(p, "double", nums, tt.workers, 2, func(ctx context.Context, n int) (int, error) {
+				return n * 2, nil
+			})
+			strs := Stage(p, "format", doubled, tt.workers, 0, func(ctx context.Context, n int) (string, error) {
+				return strconv.Itoa(n), nil
+			})
+
+			sink, got := collect[string]()
+			Sink(p, "collect", strs, 1, sink)
+
+			require.NoError(t, p.Wait())
+
+			result := got()
+			if !tt.ordered {
+				slices.Sort(result)
+			}
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestAsyncUtil_Pipeline_Failures(t *testing.T) {
+	errBadRow := errors.New("bad row")
+	errRead := errors.New("read failed")
+
+	tests := []struct {
+		name          string
+		source        func(ctx context.Context, emit func(int) error) error
+		stage         func(ctx context.Context, n int) (int, error)
+		sink          func(ctx context.Context, n int) error
+		expectedStage string
+		expectedIndex int
+		expectedItem  any
+		expectedErr   error
+		expectedPanic any
+	}{
+		{
+			name:          "stage error reports the item",
+			source:        sliceSource([]int{10, 20, 30, 40}),
+			stage:         func(ctx context.Context, n int) (int, error) { return n, errIf(n == 30, errBadRow) },
+			expectedStage: "validate",
+			expectedIndex: 2,
+			expectedItem:  30,
+			expectedErr:   errBadRow,
+		},
+		{
+			name:          "stage panic",
+			source:        sliceSource([]int{10, 20}),
+			stage:         func(ctx context.Context, n int) (int, error) { panic("stage boom") },
+			expectedStage: "validate",
+			expectedIndex: 0,
+			expectedItem:  10,
+			expectedPanic: "stage boom",
+		},
+		{
+			name: "source error",
+			source: func(ctx context.Context, emit func(int) error) error {
+				emit(1)
+				return errRead
+			},
+			expectedStage: "read",
+			expectedIndex: -1,
+			expectedErr:   errRead,
+		},
+		{
+			name:          "sink error",
+			source:        sliceSource([]int{1, 2, 3}),
+			sink:          func(ctx context.Context, n int) error { return errIf(n == 2, errBadRow) },
+			expectedStage: "save",
+			expectedIndex: 1,
+			expectedItem:  2,
+			expectedErr:   errBadRow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := NewPipeline(context.Background())
+
+			stage := tt.stage
+			if stage == nil {
+				stage = func(ctx context.Context, n int) (int, error) { return n, nil }
+			}
+			sink := tt.sink
+			if sink == nil {
+				sink = func(ctx context.Context, n int) error { return nil }
+			}
+
+			rows := Source(p, "read", 0, tt.source)
+			valid := Stage(p, "validate", rows, 1, 0, stage)
+			Sink(p, "save", valid, 1, sink)
+
+			err := p.Wait()
+
+			var stageErr *StageError
+			require.ErrorAs(t, err, &stageErr)
+			assert.Equal(t, tt.expectedStage, stageErr.Stage)
+			assert.Equal(t, tt.expectedIndex, stageErr.Index)
+			assert.Equal(t, tt.expectedItem, stageErr.Item)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			}
+			if tt.expectedPanic != nil {
+				var perr *PanicError
+				require.ErrorAs(t, err, &perr)
+				assert.Equal(t, tt.expectedPanic, perr.Value)
+				assert.Equal(t, tt.expectedStage, perr.Task)
+			}
+		})
+	}
+}
+
+func TestAsyncUtil_Pipeline_FailureCancelsOtherStages(t *testing.T) {
+	errFailed := errors.New("failed")
+	p, ctx := NewPipeline(context.Background())
+
+	// An endless source only stops when the pipeline is cancelled.
+	var emitted atomic.Int64
+	nums := Source(p, "endless", 0, func(ctx context.Context, emit func(int) error) error {
+		for i := 0; ; i++ {
+			if err := emit(i); err != nil {
+				return err
+			}
+			emitted.Add(1)
+		}
+	})
+
+	Sink(p, "fail", nums, 1, func(ctx context.Context, n int) error {
+		return errIf(n == 5, errFailed)
+	})
+
+	err := p.Wait()
+	assert.ErrorIs(t, err, errFailed)
+	assert.Error(t, ctx.Err(), "pipeline context is cancelled")
+	assert.LessOrEqual(t, emitted.Load(), int64(7), "backpressure stops the source soon after the failure")
+}
+
+func TestAsyncUtil_Pipeline_ParentCancelled(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	p, _ := NewPipeline(parent)
+
+	started := make(chan struct{})
+	nums := Source(p, "endless", 0, func(ctx context.Context, emit func(int) error) error {
+		close(started)
+		for {
+			if err := emit(1); err != nil {
+				return err
+			}
+		}
+	})
+	Sink(p, "slow", nums, 1, func(ctx context.Context, n int) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	<-started
+	cancel()
+
+	assert.ErrorIs(t, p.Wait(), context.Canceled)
+}
+
+func TestAsyncUtil_Pipeline_Backpressure(t *testing.T) {
+	p, _ := NewPipeline(context.Background())
+
+	var emitted atomic.Int64
+	nums := Source(p, "read", 3, func(ctx context.Context, emit func(int) error) error {
+		for i := range 100 {
+			if err := emit(i); err != nil {
+				return err
+			}
+			emitted.Add(1)
+		}
+		return nil
+	})
+
+	release := make(chan struct{})
+	var consumed atomic.Int64
+	Sink(p, "blocked", nums, 1, func(ctx context.Context, n int) error {
+		<-release
+		consumed.Add(1)
+		return nil
+	})
+
+	// One item held by the sink, three in the buffer.
+	assert.Eventually(t, func() bool { return emitted.Load() == 4 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int64(4), emitted.Load())
+
+	close(release)
+	require.NoError(t, p.Wait())
+	assert.Equal(t, int64(100), consumed.Load())
+}
+
+func TestAsyncUtil_Pipeline_FanOutFanIn(t *testing.T) {
+	p, _ := NewPipeline(context.Background())
+
+	nums := Source(p, "numbers", 0, sliceSource([]int{1, 2, 3}))
+	outs := FanOut(p, nums, 2, 1)
+	require.Len(t, outs, 2)
+
+	tens := Stage(p, "tens", outs[0], 1, 0, func(ctx context.Context, n int) (int, error) { return n * 10, nil })
+	hundreds := Stage(p, "hundreds", outs[1], 1, 0, func(ctx context.Context, n int) (int, error) { return n * 100, nil })
+
+	sink, got := collect[int]()
+	Sink(p, "collect", FanIn(p, 0, tens, hundreds), 2, sink)
+
+	require.NoError(t, p.Wait())
+
+	result := got()
+	slices.Sort(result)
+	assert.Equal(t, []int{10, 20, 30, 100, 200, 300}, result)
+}
+
+func TestAsyncUtil_StageError(t *testing.T) {
+	errBad := errors.New("bad")
+
+	tests := []struct {
+		name     string
+		err      *StageError
+		expected string
+	}{
+		{name: "item failure", err: &StageError{Stage: "parse", Index: 3, Item: "x", Err: errBad}, expected: "stage parse: item 3: bad"},
+		{name: "source failure", err: &StageError{Stage: "read", Index: -1, Err: errBad}, expected: "stage read: bad"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, tt.err, tt.expected)
+			assert.ErrorIs(t, tt.err, errBad)
+		})
+	}
+}
+
+func BenchmarkAsyncUtil_Pipeline(b *testing.B) {
+	items := make([]int, 1000)
+	for i := range items {
+		items[i] = i
+	}
+
+	for b.Loop() {
+		p, _ := NewPipeline(context.Background())
+		nums := Source(p, "numbers", 64, sliceSource(items))
+		doubled := Stage(p, "double", nums, 4, 64, func(ctx context.Context, n int) (int, error) { return n * 2, nil })
+		Sink(p, "discard", doubled, 1, func(ctx context.Context, n int) error { return nil })
+		p.Wait()
+	}
+}
@@ -0,0 +1,174 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrPoolClosed is returned when submitting to a pool that is shutting down.
+	ErrPoolClosed = errors.New("asyncutil: pool is closed")
+
+	// ErrPoolFull is returned by TrySubmit when the queue has no free slot.
+	ErrPoolFull = errors.New("asyncutil: pool queue is full")
+)
+
+// Pool runs tasks on a fixed number of workers with a bounded queue.
+// Tasks are protected the same way as SafeGo: panics are recovered, reported to
+// OnPanic and returned as the task error.
+//
+// Example:
+//
+//	pool := NewPool[User](10, 100)
+//	defer pool.Shutdown(context.Background())
+//
+//	for _, id := range ids {
+//	    ch, err := pool.Submit(ctx, func(ctx context.Context) (User, error) {
+//	        return repo.FindUser(ctx, id)
+//	    })
+//	    if err != nil {
+//	        return err
+//	    }
+//	    results = append(results, ch)
+//	}
+type Pool[T any] struct {
+	name     string
+	tasks    chan func()
+	quit     chan struct{}
+	done     chan struct{}
+	workers  sync.WaitGroup
+	mu       sync.RWMutex
+	closed   bool
+	shutdown sync.Once
+}
+
+// NewPool starts a pool with the given number of workers and queue capacity.
+// workers is at least 1. A queueSize of 0 hands tasks directly to idle workers.
+func NewPool[T any](workers, queueSize int) *Pool[T] {
+	workers = max(workers, 1)
+	queueSize = max(queueSize, 0)
+
+	p := &Pool[T]{
+		tasks: make(chan func(), queueSize),
+		quit:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+
+	p.workers.Add(workers)
+	for range workers {
+		go func() {
+			defer p.workers.Done()
+			for task := range p.tasks {
+				reportQueueDepth(p.name, len(p.tasks))
+				task()
+			}
+		}()
+	}
+
+	go func() {
+		p.workers.Wait()
+		close(p.done)
+	}()
+
+	return p
+}
+
+// SetName names the pool in the queue depth reported to TaskMetrics. It must
+// be called before Submit.
+func (p *Pool[T]) SetName(name string) {
+	p.name = name
+}
+
+// Submit queues fn and returns a channel that yields its result. When the queue
+// is full it blocks until a slot frees up (backpressure), ctx is done, or the pool
+// shuts down.
+//
+// ctx is passed to fn. If ctx is done before a worker picks the task up, fn is
+// skipped and the result holds ctx.Err().
+func (p *Pool[T]) Submit(ctx context.Context, fn func(ctx context.Context) (T, error)) (<-chan Result[T], error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return nil, ErrPoolClosed
+	}
+
+	ch, task := p.newTask(ctx, fn)
+
+	select {
+	case p.tasks <- task:
+		reportQueueDepth(p.name, len(p.tasks))
+		return ch, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.quit:
+		return nil, ErrPoolClosed
+	}
+}
+
+// TrySubmit queues fn without blocking. It returns ErrPoolFull when the queue
+// has no free slot.
+func (p *Pool[T]) TrySubmit(ctx context.Context, fn func(ctx context.Context) (T, error)) (<-chan Result[T], error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return nil, ErrPoolClosed
+	}
+
+	ch, task := p.newTask(ctx, fn)
+
+	select {
+	case p.tasks <- task:
+		reportQueueDepth(p.name, len(p.tasks))
+		return ch, nil
+	default:
+		return nil, ErrPoolFull
+	}
+}
+
+// QueueLen returns the number of tasks waiting for a worker.
+func (p *Pool[T]) QueueLen() int {
+	return len(p.tasks)
+}
+
+// Shutdown stops accepting tasks and waits for queued and running tasks to finish.
+// If ctx is done first, it returns ctx.Err() and the remaining tasks keep
+// draining in the background. Calling Shutdown more than once is safe.
+func (p *Pool[T]) Shutdown(ctx context.Context) error {
+	p.shutdown.Do(func() {
+		// Unblock pending Submit calls, then wait for them to leave before closing the queue
+		close(p.quit)
+
+		p.mu.Lock()
+		p.closed = true
+		p.mu.Unlock()
+
+		close(p.tasks)
+	})
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Pool[T]) newTask(ctx context.Context, fn func(ctx context.Context) (T, error)) (<-chan Result[T], func()) {
+	ch := make(chan Result[T], 1)
+
+	task := func() {
+		if err := ctx.Err(); err != nil {
+			var zero T
+			ch <- Result[T]{Value: zero, Err: err}
+			return
+		}
+
+		val, err := runTask(ctx, func() (T, error) { return fn(ctx) })
+		ch <- Result[T]{Value: val, Err: err}
+	}
+
+	return ch, task
+}
@@ -0,0 +1,244 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsyncUtil_Pool(t *testing.T) {
+	tests := []struct {
+		name        string
+		fn          func(ctx context.Context) (int, error)
+		expected    int
+		errContains string
+	}{
+		{
+			name: "success",
+			fn: func(ctx context.Context) (int, error) {
+				return 42, nil
+			},
+			expected: 42,
+		},
+		{
+			name: "error",
+			fn: func(ctx context.Context) (int, error) {
+				return 0, errors.New("task failed")
+			},
+			errContains: "task failed",
+		},
+		{
+			name: "panic",
+			fn: func(ctx context.Context) (int, error) {
+				panic("pool boom")
+			},
+			errContains: "panic recovered: pool boom",
+		},
+	}
+
+	pool := NewPool[int](2, 4)
+	defer pool.Shutdown(context.Background())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch, err := pool.Submit(context.Background(), tt.fn)
+			require.NoError(t, err)
+
+			res := <-ch
+
+			assert.Equal(t, tt.expected, res.Value)
+			if tt.errContains != "" {
+				assert.ErrorContains(t, res.Err, tt.errContains)
+			} else {
+				assert.NoError(t, res.Err)
+			}
+		})
+	}
+}
+
+func TestAsyncUtil_Pool_Concurrency(t *testing.T) {
+	const workers = 3
+
+	pool := NewPool[int](workers, 100)
+
+	var running, peak atomic.Int32
+	results := make([]<-chan Result[int], 0, 30)
+
+	for i := range 30 {
+		ch, err := pool.Submit(context.Background(), func(ctx context.Context) (int, error) {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			return i, nil
+		})
+		require.NoError(t, err)
+		results = append(results, ch)
+	}
+
+	for i, ch := range results {
+		res := <-ch
+		assert.NoError(t, res.Err)
+		assert.Equal(t, i, res.Value)
+	}
+
+	assert.LessOrEqual(t, peak.Load(), int32(workers))
+	assert.NoError(t, pool.Shutdown(context.Background()))
+}
+
+func TestAsyncUtil_Pool_Backpressure(t *testing.T) {
+	pool := NewPool[int](1, 1)
+	release := make(chan struct{})
+
+	block := func(ctx context.Context) (int, error) {
+		<-release
+		return 1, nil
+	}
+
+	// one task running, one queued
+	_, err := pool.Submit(context.Background(), block)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return pool.QueueLen() == 0 }, time.Second, time.Millisecond)
+	_, err = pool.Submit(context.Background(), block)
+	require.NoError(t, err)
+
+	t.Run("TrySubmit reports full queue", func(t *testing.T) {
+		_, err := pool.TrySubmit(context.Background(), block)
+		assert.ErrorIs(t, err, ErrPoolFull)
+	})
+
+	t.Run("Submit blocks until ctx is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := pool.Submit(ctx, block)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	close(release)
+	assert.NoError(t, pool.Shutdown(context.Background()))
+}
+
+func TestAsyncUtil_Pool_Shutdown(t *testing.T) {
+	t.Run("drains queued tasks", func(t *testing.T) {
+		pool := NewPool[int](1, 10)
+
+		var completed atomic.Int32
+		for range 5 {
+			_, err := pool.Submit(context.Background(), func(ctx context.Context) (int, error) {
+				time.Sleep(time.Millisecond)
+				completed.Add(1)
+				return 0, nil
+			})
+			require.NoError(t, err)
+		}
+
+		assert.NoError(t, pool.Shutdown(context.Background()))
+		assert.Equal(t, int32(5), completed.Load())
+	})
+
+	t.Run("rejects tasks after shutdown", func(t *testing.T) {
+		pool := NewPool[int](1, 1)
+		require.NoError(t, pool.Shutdown(context.Background()))
+
+		_, err := pool.Submit(context.Background(), func(ctx context.Context) (int, error) { return 0, nil })
+		assert.ErrorIs(t, err, ErrPoolClosed)
+
+		_, err = pool.TrySubmit(context.Background(), func(ctx context.Context) (int, error) { return 0, nil })
+		assert.ErrorIs(t, err, ErrPoolClosed)
+
+		assert.NoError(t, pool.Shutdown(context.Background()), "second shutdown should be safe")
+	})
+
+	t.Run("returns ctx error when tasks outlive the deadline", func(t *testing.T) {
+		pool := NewPool[int](1, 1)
+		release := make(chan struct{})
+		defer close(release)
+
+		_, err := pool.Submit(context.Background(), func(ctx context.Context) (int, error) {
+			<-release
+			return 0, nil
+		})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, pool.Shutdown(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("unblocks pending Submit", func(t *testing.T) {
+		pool := NewPool[int](1, 0)
+		release := make(chan struct{})
+
+		_, err := pool.Submit(context.Background(), func(ctx context.Context) (int, error) {
+			<-release
+			return 0, nil
+		})
+		require.NoError(t, err)
+
+		submitErr := make(chan error, 1)
+		go func() {
+			_, err := pool.Submit(context.Background(), func(ctx context.Context) (int, error) { return 0, nil })
+			submitErr <- err
+		}()
+
+		time.Sleep(5 * time.Millisecond)
+		shutdownErr := make(chan error, 1)
+		go func() { shutdownErr <- pool.Shutdown(context.Background()) }()
+
+		assert.ErrorIs(t, <-submitErr, ErrPoolClosed)
+		close(release)
+		assert.NoError(t, <-shutdownErr)
+	})
+}
+
+func TestAsyncUtil_Pool_SkipsCancelledTasks(t *testing.T) {
+	pool := NewPool[int](1, 1)
+	release := make(chan struct{})
+
+	_, err := pool.Submit(context.Background(), func(ctx context.Context) (int, error) {
+		<-release
+		return 0, nil
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var ran atomic.Bool
+	ch, err := pool.Submit(ctx, func(ctx context.Context) (int, error) {
+		ran.Store(true)
+		return 1, nil
+	})
+	require.NoError(t, err)
+
+	cancel()
+	close(release)
+
+	res := <-ch
+	assert.ErrorIs(t, res.Err, context.Canceled)
+	assert.False(t, ran.Load())
+	assert.NoError(t, pool.Shutdown(context.Background()))
+}
+
+func BenchmarkAsyncUtil_Pool(b *testing.B) {
+	pool := NewPool[int](4, 64)
+	defer pool.Shutdown(context.Background())
+
+	ctx := context.Background()
+	for b.Loop() {
+		ch, _ := pool.Submit(ctx, func(ctx context.Context) (int, error) {
+			return 42, nil
+		})
+		<-ch
+	}
+}
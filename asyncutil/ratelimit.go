@@ -0,0 +1,312 @@
+package asyncutil
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shoraid/stx-go-utils/apperror"
+	"github.com/shoraid/stx-go-utils/httpresponse"
+)
+
+// LimiterConfig configures a Limiter or KeyedLimiter.
+type LimiterConfig struct {
+	// Rate is the number of events allowed per second on average. Rate <= 0
+	// disables limiting.
+	Rate float64
+
+	// Burst is the number of events allowed at once, i.e. the bucket size.
+	// Defaults to 1.
+	Burst int
+
+	// Clock is used to refill the bucket and to wait. Defaults to RealClock.
+	Clock Clock
+}
+
+// Limiter is a token-bucket rate limiter. The bucket starts full, holds up to
+// Burst tokens and refills at Rate tokens per second. Every event takes one token.
+//
+// Example:
+//
+//	// 50 requests per second with bursts of up to 100.
+//	limiter := NewLimiter(LimiterConfig{Rate: 50, Burst: 100})
+//
+//	for _, job := range jobs {
+//	    if err := limiter.Wait(ctx); err != nil {
+//	        return err
+//	    }
+//	    client.Send(ctx, job)
+//	}
+type Limiter struct {
+	rate  float64
+	burst float64
+	clock Clock
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a Limiter with a full bucket.
+func NewLimiter(cfg LimiterConfig) *Limiter {
+	clock := clockOrReal(cfg.Clock)
+	burst := float64(max(cfg.Burst, 1))
+
+	return &Limiter{
+		rate:   cfg.Rate,
+		burst:  burst,
+		clock:  clock,
+		tokens: burst,
+		last:   clock.Now(),
+	}
+}
+
+// Allow takes a token if one is available and reports whether it did.
+// It never blocks.
+func (l *Limiter) Allow() bool {
+	ok, _ := l.allow()
+	return ok
+}
+
+// Wait blocks until a token is available and takes it. It returns ctx.Err()
+// if the context is done first; the token is then given back.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if l.rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	l.refill(l.clock.Now())
+	l.tokens--
+	delay := l.delayLocked(0)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-l.clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens = min(l.tokens+1, l.burst)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// allow takes a token if one is available. Otherwise it returns how long until
+// the next token.
+func (l *Limiter) allow() (bool, time.Duration) {
+	if l.rate <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(l.clock.Now())
+	if l.tokens >= 1 {
+		l.tokens--
+		return true, 0
+	}
+
+	return false, l.delayLocked(1)
+}
+
+// refill adds the tokens earned since the last refill.
+func (l *Limiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = min(l.tokens+elapsed.Seconds()*l.rate, l.burst)
+		l.last = now
+	}
+}
+
+// delayLocked returns how long until the bucket holds at least `want` tokens.
+func (l *Limiter) delayLocked(want float64) time.Duration {
+	missing := want - l.tokens
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(missing / l.rate * float64(time.Second)))
+}
+
+// KeyedLimiter keeps a separate Limiter per key, e.g. per tenant or client IP.
+// Keys unused for the idle TTL are evicted, so memory stays bounded by the
+// number of active keys.
+//
+// Example:
+//
+//	// 10 requests per second per tenant.
+//	perTenant := NewKeyedLimiter[string](LimiterConfig{Rate: 10, Burst: 20}, 10*time.Minute)
+//
+//	if !perTenant.Allow(tenantID) {
+//	    return apperror.Err429TooManyRequests
+//	}
+type KeyedLimiter[K comparable] struct {
+	cfg     LimiterConfig
+	idleTTL time.Duration
+	clock   Clock
+
+	mu        sync.Mutex
+	limiters  map[K]*keyedLimiter
+	lastSweep time.Time
+}
+
+type keyedLimiter struct {
+	limiter  *Limiter
+	lastUsed time.Time
+}
+
+// NewKeyedLimiter returns a KeyedLimiter applying cfg to every key. An idleTTL
+// <= 0 defaults to 10 minutes. Pick an idleTTL of at least Burst/Rate seconds,
+// so an evicted key would have had a full bucket anyway.
+func NewKeyedLimiter[K comparable](cfg LimiterConfig, idleTTL time.Duration) *KeyedLimiter[K] {
+	if idleTTL <= 0 {
+		idleTTL = 10 * time.Minute
+	}
+
+	clock := clockOrReal(cfg.Clock)
+
+	return &KeyedLimiter[K]{
+		cfg:       cfg,
+		idleTTL:   idleTTL,
+		clock:     clock,
+		limiters:  make(map[K]*keyedLimiter),
+		lastSweep: clock.Now(),
+	}
+}
+
+// Allow takes a token for key if one is available and reports whether it did.
+func (k *KeyedLimiter[K]) Allow(key K) bool {
+	return k.get(key).Allow()
+}
+
+// Wait blocks until a token for key is available and takes it, like Limiter.Wait.
+func (k *KeyedLimiter[K]) Wait(ctx context.Context, key K) error {
+	return k.get(key).Wait(ctx)
+}
+
+// Len returns the number of keys currently tracked.
+func (k *KeyedLimiter[K]) Len() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return len(k.limiters)
+}
+
+// get returns the limiter for key, creating it if needed. Idle keys are swept
+// at most once per idle TTL.
+func (k *KeyedLimiter[K]) get(key K) *Limiter {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := k.clock.Now()
+
+	if now.Sub(k.lastSweep) >= k.idleTTL {
+		for key, entry := range k.limiters {
+			if now.Sub(entry.lastUsed) >= k.idleTTL {
+				delete(k.limiters, key)
+			}
+		}
+		k.lastSweep = now
+	}
+
+	entry, ok := k.limiters[key]
+	if !ok {
+		entry = &keyedLimiter{limiter: NewLimiter(k.cfg)}
+		k.limiters[key] = entry
+	}
+	entry.lastUsed = now
+
+	return entry.limiter
+}
+
+// WithLimiter wraps fn so that every call first waits for a token from l.
+// The result can be passed to SafeGoCtx, Retry or Group.
+//
+// Example:
+//
+//	send := WithLimiter(quota, func(ctx context.Context) (Receipt, error) {
+//	    return mailer.Send(ctx, msg)
+//	})
+//
+//	receipt, err := Retry(ctx, RetryPolicy{}, send)
+func WithLimiter[T any](l *Limiter, fn func(ctx context.Context) (T, error)) func(ctx context.Context) (T, error) {
+	return func(ctx context.Context) (T, error) {
+		if err := l.Wait(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+		return fn(ctx)
+	}
+}
+
+// SafeGoLimited is SafeGoCtx under a rate limit: fn runs in a new goroutine
+// once l hands out a token. If ctx is done while waiting, fn is not run and
+// the result holds ctx.Err().
+//
+// Example:
+//
+//	quota := NewLimiter(LimiterConfig{Rate: 50, Burst: 100})
+//
+//	chans := make([]<-chan Result[Invoice], len(ids))
+//	for i, id := range ids {
+//	    chans[i] = SafeGoLimited(ctx, quota, func(ctx context.Context) (Invoice, error) {
+//	        return billing.FetchInvoice(ctx, id)
+//	    })
+//	}
+//
+//	invoices, err := All(ctx, chans...)
+func SafeGoLimited[T any](ctx context.Context, l *Limiter, fn func(ctx context.Context) (T, error)) <-chan Result[T] {
+	return SafeGoCtx(ctx, WithLimiter(l, fn))
+}
+
+// RateLimitMiddleware limits requests per key with l. Rejected requests get a
+// 429 response through httpresponse.HandleError and a Retry-After header.
+// A nil keyFunc uses ClientIPKey.
+//
+// Example:
+//
+//	limiter := NewKeyedLimiter[string](LimiterConfig{Rate: 5, Burst: 10}, 0)
+//	mux.Handle("/api/", RateLimitMiddleware(limiter, func(r *http.Request) string {
+//	    return r.Header.Get("X-Tenant-ID")
+//	})(apiHandler))
+func RateLimitMiddleware(l *KeyedLimiter[string], keyFunc func(r *http.Request) string) func(http.Handler) http.Handler {
+	if keyFunc == nil {
+		keyFunc = ClientIPKey
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ok, retryAfter := l.get(keyFunc(r)).allow()
+			if !ok {
+				seconds := max(int(math.Ceil(retryAfter.Seconds())), 1)
+				w.Header().Set("Retry-After", strconv.Itoa(seconds))
+				httpresponse.HandleError(w, apperror.Err429TooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIPKey returns the host part of r.RemoteAddr. It does not look at
+// X-Forwarded-For; behind a proxy, pass a keyFunc that reads the trusted header.
+func ClientIPKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
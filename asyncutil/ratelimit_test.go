@@ -0,0 +1,248 @@
+package asyncutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shoraid/stx-go-utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClock() *FakeClock {
+	return NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestAsyncUtil_Limiter_Allow(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      LimiterConfig
+		steps    []time.Duration // advance the clock before each call
+		expected []bool
+	}{
+		{
+			name:     "burst then empty",
+			cfg:      LimiterConfig{Rate: 1, Burst: 3},
+			steps:    []time.Duration{0, 0, 0, 0},
+			expected: []bool{true, true, true, false},
+		},
+		{
+			name:     "refills over time",
+			cfg:      LimiterConfig{Rate: 10, Burst: 1},
+			steps:    []time.Duration{0, 0, 50 * time.Millisecond, 50 * time.Millisecond},
+			expected: []bool{true, false, false, true},
+		},
+		{
+			name:     "refill is capped at burst",
+			cfg:      LimiterConfig{Rate: 100, Burst: 2},
+			steps:    []time.Duration{time.Hour, 0, 0},
+			expected: []bool{true, true, false},
+		},
+		{
+			name:     "default burst is 1",
+			cfg:      LimiterConfig{Rate: 1},
+			steps:    []time.Duration{0, 0},
+			expected: []bool{true, false},
+		},
+		{
+			name:     "no rate means no limit",
+			cfg:      LimiterConfig{},
+			steps:    []time.Duration{0, 0, 0},
+			expected: []bool{true, true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newTestClock()
+			tt.cfg.Clock = clock
+			l := NewLimiter(tt.cfg)
+
+			got := make([]bool, 0, len(tt.steps))
+			for _, step := range tt.steps {
+				clock.Advance(step)
+				got = append(got, l.Allow())
+			}
+
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestAsyncUtil_Limiter_Wait(t *testing.T) {
+	t.Run("waits for the next token", func(t *testing.T) {
+		clock := newInstantClock()
+		l := NewLimiter(LimiterConfig{Rate: 10, Burst: 2, Clock: clock})
+
+		for range 4 {
+			require.NoError(t, l.Wait(context.Background()))
+		}
+
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 100 * time.Millisecond}, clock.waits)
+	})
+
+	t.Run("returns the token when cancelled", func(t *testing.T) {
+		clock := newTestClock()
+		l := NewLimiter(LimiterConfig{Rate: 1, Burst: 1, Clock: clock})
+		require.True(t, l.Allow())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+
+		clock.Advance(time.Second)
+		assert.True(t, l.Allow())
+	})
+
+	t.Run("done context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		l := NewLimiter(LimiterConfig{Rate: 1, Burst: 1})
+		assert.ErrorIs(t, l.Wait(ctx), context.Canceled)
+		assert.True(t, l.Allow())
+	})
+}
+
+func TestAsyncUtil_KeyedLimiter(t *testing.T) {
+	clock := newTestClock()
+	k := NewKeyedLimiter[string](LimiterConfig{Rate: 1, Burst: 1, Clock: clock}, time.Minute)
+
+	assert.True(t, k.Allow("acme"))
+	assert.False(t, k.Allow("acme"))
+	assert.True(t, k.Allow("globex"), "keys have separate buckets")
+	assert.Equal(t, 2, k.Len())
+
+	clock.Advance(30 * time.Second)
+	assert.NoError(t, k.Wait(context.Background(), "acme"))
+
+	// globex has been idle for a minute and is evicted on the next access.
+	clock.Advance(30 * time.Second)
+	assert.True(t, k.Allow("initech"))
+	assert.Equal(t, 2, k.Len())
+}
+
+func TestAsyncUtil_SafeGoLimited(t *testing.T) {
+	clock := newInstantClock()
+	l := NewLimiter(LimiterConfig{Rate: 2, Burst: 1, Clock: clock})
+
+	var calls atomic.Int32
+	fn := func(ctx context.Context) (int32, error) {
+		return calls.Add(1), nil
+	}
+
+	for range 3 {
+		res := <-SafeGoLimited(context.Background(), l, fn)
+		require.NoError(t, res.Err)
+	}
+
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}, clock.waits)
+
+	t.Run("cancelled while waiting does not run fn", func(t *testing.T) {
+		l := NewLimiter(LimiterConfig{Rate: 1, Burst: 1, Clock: newTestClock()})
+		l.Allow()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		ran := false
+		res := <-SafeGoLimited(ctx, l, func(ctx context.Context) (int, error) {
+			ran = true
+			return 1, nil
+		})
+
+		assert.ErrorIs(t, res.Err, context.DeadlineExceeded)
+		assert.False(t, ran)
+	})
+
+	t.Run("recovers panic", func(t *testing.T) {
+		res := <-SafeGoLimited(context.Background(), NewLimiter(LimiterConfig{}), func(ctx context.Context) (int, error) {
+			panic("limited boom")
+		})
+
+		var perr *PanicError
+		assert.True(t, errors.As(res.Err, &perr))
+	})
+}
+
+func TestAsyncUtil_RateLimitMiddleware(t *testing.T) {
+	clock := newTestClock()
+	limiter := NewKeyedLimiter[string](LimiterConfig{Rate: 0.5, Burst: 2, Clock: clock}, 0)
+
+	handler := RateLimitMiddleware(limiter, func(r *http.Request) string {
+		return r.Header.Get("X-Tenant-ID")
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name               string
+		tenant             string
+		expectedCode       int
+		expectedRetryAfter string
+	}{
+		{"first request", "acme", http.StatusNoContent, ""},
+		{"burst", "acme", http.StatusNoContent, ""},
+		{"over the limit", "acme", http.StatusTooManyRequests, "2"},
+		{"other tenant", "globex", http.StatusNoContent, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/orders", nil)
+			req.Header.Set("X-Tenant-ID", tt.tenant)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Equal(t, tt.expectedRetryAfter, rec.Header().Get("Retry-After"))
+
+			if tt.expectedCode == http.StatusTooManyRequests {
+				var body map[string]any
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				assert.Equal(t, string(apperror.TOO_MANY_REQUESTS_CODE), body["code"])
+			}
+		})
+	}
+}
+
+func TestAsyncUtil_ClientIPKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		expected   string
+	}{
+		{"ipv4 with port", "203.0.113.7:52100", "203.0.113.7"},
+		{"ipv6 with port", "[2001:db8::1]:443", "2001:db8::1"},
+		{"no port", "203.0.113.7", "203.0.113.7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+
+			assert.Equal(t, tt.expected, ClientIPKey(req))
+		})
+	}
+}
+
+func BenchmarkAsyncUtil_KeyedLimiter_Allow(b *testing.B) {
+	k := NewKeyedLimiter[string](LimiterConfig{Rate: 1e9, Burst: 1000}, 0)
+	keys := []string{"acme", "globex", "initech", "umbrella"}
+
+	i := 0
+	for b.Loop() {
+		k.Allow(keys[i%len(keys)])
+		i++
+	}
+}
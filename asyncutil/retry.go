@@ -0,0 +1,212 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/shoraid/stx-go-utils/apperror"
+)
+
+// RetryPolicy configures Retry. The zero value is usable: 3 attempts, 100ms
+// initial delay doubling on every retry with full jitter, no delay cap and no
+// elapsed time limit.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of calls, including the first one.
+	MaxAttempts int
+
+	// InitialDelay is the backoff before the first retry.
+	InitialDelay time.Duration
+
+	// MaxDelay caps the backoff between attempts. 0 means no cap other than the
+	// largest time.Duration, where the backoff stops growing.
+	MaxDelay time.Duration
+
+	// Multiplier grows the backoff after every retry. Values below 1 default to 2.
+	Multiplier float64
+
+	// MaxElapsed stops retrying when the next wait would exceed this duration since
+	// the first attempt. 0 means no limit.
+	MaxElapsed time.Duration
+
+	// Retryable decides whether err is worth retrying. Defaults to IsRetryable.
+	Retryable func(err error) bool
+
+	// OnRetry is called before waiting for the next attempt, e.g. for logging.
+	// attempt is the number of the attempt that just failed, starting at 1.
+	OnRetry func(attempt int, err error, delay time.Duration)
+
+	// Jitter turns the computed backoff into the actual wait. Defaults to full
+	// jitter, a random duration in [0, backoff].
+	Jitter func(backoff time.Duration) time.Duration
+
+	// Clock is used for waiting and measuring elapsed time. Defaults to RealClock.
+	Clock Clock
+}
+
+// nonRetryableErrors are client errors that will fail the same way on every attempt.
+var nonRetryableErrors = []error{
+	apperror.Err400InvalidAction,
+	apperror.Err400InvalidBody,
+	apperror.Err400InvalidData,
+	apperror.Err400InvalidParams,
+	apperror.Err401Unauthorized,
+	apperror.Err403Forbidden,
+	apperror.Err403NoTenant,
+	apperror.Err403CSRFTokenMismatch,
+	apperror.Err404RecordNotFound,
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not retryable, regardless of the policy predicate.
+// Retry returns the wrapped error. Returns nil if err is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsRetryable is the default retry predicate. Context errors, errors marked with
+// Permanent and the apperror 4xx sentinels are not retried; everything else is.
+func IsRetryable(err error) bool {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	for _, target := range nonRetryableErrors {
+		if errors.Is(err, target) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Retry calls fn until it succeeds, returns a non-retryable error, or the policy
+// runs out of attempts or time. Waits use exponential backoff with jitter.
+// Panics in fn are recovered like SafeGo and returned as errors.
+//
+// It returns the result of the last attempt, or ctx.Err() if ctx is done while
+// waiting between attempts.
+//
+// Example:
+//
+//	policy := RetryPolicy{
+//	    MaxAttempts:  5,
+//	    InitialDelay: 200 * time.Millisecond,
+//	    MaxDelay:     5 * time.Second,
+//	    MaxElapsed:   20 * time.Second,
+//	    OnRetry: func(attempt int, err error, delay time.Duration) {
+//	        slog.Warn("payment call failed, retrying", "attempt", attempt, "delay", delay, "err", err)
+//	    },
+//	}
+//
+//	receipt, err := Retry(ctx, policy, func(ctx context.Context) (Receipt, error) {
+//	    return paymentClient.Charge(ctx, req)
+//	})
+func Retry[T any](ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) (T, error)) (T, error) {
+	policy = policy.withDefaults()
+
+	start := policy.Clock.Now()
+	backoff := policy.InitialDelay
+
+	for attempt := 1; ; attempt++ {
+		val, err := safeCall(ctx, func() (T, error) { return fn(ctx) })
+		if err == nil {
+			return val, nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return val, permanent.err
+		}
+
+		if attempt >= policy.MaxAttempts || !policy.Retryable(err) || ctx.Err() != nil {
+			return val, err
+		}
+
+		delay := policy.Jitter(backoff)
+		if policy.MaxElapsed > 0 && delay > policy.MaxElapsed-policy.Clock.Now().Sub(start) {
+			return val, err
+		}
+
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, err, delay)
+		}
+
+		select {
+		case <-policy.Clock.After(delay):
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+
+		backoff = policy.nextBackoff(backoff)
+	}
+}
+
+// nextBackoff grows backoff by the multiplier up to MaxDelay, or up to the
+// largest time.Duration without a cap. The product is compared in float64 so
+// it cannot overflow into a negative duration.
+func (p RetryPolicy) nextBackoff(backoff time.Duration) time.Duration {
+	limit := time.Duration(math.MaxInt64)
+	if p.MaxDelay > 0 {
+		limit = p.MaxDelay
+	}
+
+	next := float64(backoff) * p.Multiplier
+	if next >= float64(limit) {
+		return limit
+	}
+
+	return time.Duration(next)
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = 100 * time.Millisecond
+	}
+	if p.MaxDelay > 0 && p.InitialDelay > p.MaxDelay {
+		p.InitialDelay = p.MaxDelay
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	if p.Retryable == nil {
+		p.Retryable = IsRetryable
+	}
+	if p.Jitter == nil {
+		p.Jitter = fullJitter
+	}
+	p.Clock = clockOrReal(p.Clock)
+
+	return p
+}
+
+// fullJitter returns a random duration in [0, d].
+func fullJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	if d == math.MaxInt64 {
+		return time.Duration(rand.Int64N(int64(d)))
+	}
+	return time.Duration(rand.Int64N(int64(d) + 1))
+}
@@ -0,0 +1,305 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shoraid/stx-go-utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// instantClock is a FakeClock whose waits fire at once: After moves the time
+// forward by the requested duration and records the wait.
+type instantClock struct {
+	*FakeClock
+
+	mu    sync.Mutex
+	waits []time.Duration
+}
+
+func newInstantClock() *instantClock {
+	return &instantClock{FakeClock: NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))}
+}
+
+func (c *instantClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	c.waits = append(c.waits, d)
+	c.mu.Unlock()
+
+	ch := c.FakeClock.After(d)
+	c.Advance(d)
+	return ch
+}
+
+func noJitter(d time.Duration) time.Duration { return d }
+
+func TestAsyncUtil_Retry(t *testing.T) {
+	errFlaky := errors.New("flaky")
+
+	tests := []struct {
+		name          string
+		policy        RetryPolicy
+		failures      int
+		failWith      error
+		expected      string
+		expectedErr   error
+		expectedCalls int
+		expectedWaits []time.Duration
+	}{
+		{
+			name:          "succeeds first time",
+			policy:        RetryPolicy{},
+			expected:      "ok",
+			expectedCalls: 1,
+			expectedWaits: nil,
+		},
+		{
+			name:          "retries until success with exponential backoff",
+			policy:        RetryPolicy{MaxAttempts: 5, InitialDelay: 100 * time.Millisecond},
+			failures:      3,
+			failWith:      errFlaky,
+			expected:      "ok",
+			expectedCalls: 4,
+			expectedWaits: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond},
+		},
+		{
+			name:          "caps backoff at MaxDelay",
+			policy:        RetryPolicy{MaxAttempts: 5, InitialDelay: time.Second, Multiplier: 3, MaxDelay: 5 * time.Second},
+			failures:      4,
+			failWith:      errFlaky,
+			expected:      "ok",
+			expectedCalls: 5,
+			expectedWaits: []time.Duration{time.Second, 3 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+		{
+			name:          "gives up after MaxAttempts",
+			policy:        RetryPolicy{MaxAttempts: 3},
+			failures:      10,
+			failWith:      errFlaky,
+			expectedErr:   errFlaky,
+			expectedCalls: 3,
+			expectedWaits: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:          "stops at MaxElapsed",
+			policy:        RetryPolicy{MaxAttempts: 10, InitialDelay: time.Second, MaxElapsed: 4 * time.Second},
+			failures:      10,
+			failWith:      errFlaky,
+			expectedErr:   errFlaky,
+			expectedCalls: 3,
+			expectedWaits: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:          "does not retry apperror 4xx",
+			policy:        RetryPolicy{MaxAttempts: 5},
+			failures:      10,
+			failWith:      fmt.Errorf("lookup: %w", apperror.Err404RecordNotFound),
+			expectedErr:   apperror.Err404RecordNotFound,
+			expectedCalls: 1,
+		},
+		{
+			name:          "does not retry permanent errors",
+			policy:        RetryPolicy{MaxAttempts: 5},
+			failures:      10,
+			failWith:      Permanent(errFlaky),
+			expectedErr:   errFlaky,
+			expectedCalls: 1,
+		},
+		{
+			name: "custom predicate",
+			policy: RetryPolicy{
+				MaxAttempts: 5,
+				Retryable:   func(err error) bool { return !errors.Is(err, errFlaky) },
+			},
+			failures:      10,
+			failWith:      errFlaky,
+			expectedErr:   errFlaky,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newInstantClock()
+			tt.policy.Clock = clock
+			tt.policy.Jitter = noJitter
+
+			calls := 0
+			val, err := Retry(context.Background(), tt.policy, func(ctx context.Context) (string, error) {
+				calls++
+				if calls <= tt.failures {
+					return "", tt.failWith
+				}
+				return "ok", nil
+			})
+
+			assert.Equal(t, tt.expected, val)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedCalls, calls)
+			assert.Equal(t, tt.expectedWaits, clock.waits)
+		})
+	}
+}
+
+func TestAsyncUtil_Retry_UncappedBackoff(t *testing.T) {
+	clock := newInstantClock()
+	policy := RetryPolicy{MaxAttempts: 100, Clock: clock, Jitter: noJitter}
+
+	calls := 0
+	_, err := Retry(context.Background(), policy, func(ctx context.Context) (int, error) {
+		calls++
+		return 0, errors.New("unavailable")
+	})
+
+	assert.EqualError(t, err, "unavailable")
+	assert.Equal(t, 100, calls)
+	require.Len(t, clock.waits, 99)
+	for i := 1; i < len(clock.waits); i++ {
+		assert.GreaterOrEqual(t, clock.waits[i], clock.waits[i-1], "wait %d", i)
+	}
+	assert.Equal(t, time.Duration(math.MaxInt64), clock.waits[98])
+
+	t.Run("with full jitter", func(t *testing.T) {
+		policy := RetryPolicy{MaxAttempts: 100, Clock: newInstantClock()}
+
+		_, err := Retry(context.Background(), policy, func(ctx context.Context) (int, error) {
+			return 0, errors.New("unavailable")
+		})
+
+		assert.EqualError(t, err, "unavailable")
+	})
+}
+
+func TestAsyncUtil_Retry_OnRetry(t *testing.T) {
+	type retryEvent struct {
+		attempt int
+		err     string
+		delay   time.Duration
+	}
+
+	var events []retryEvent
+	policy := RetryPolicy{
+		MaxAttempts:  3,
+		InitialDelay: 10 * time.Millisecond,
+		Clock:        newInstantClock(),
+		Jitter:       noJitter,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			events = append(events, retryEvent{attempt, err.Error(), delay})
+		},
+	}
+
+	calls := 0
+	_, err := Retry(context.Background(), policy, func(ctx context.Context) (int, error) {
+		calls++
+		return 0, fmt.Errorf("attempt %d failed", calls)
+	})
+
+	assert.EqualError(t, err, "attempt 3 failed")
+	assert.Equal(t, []retryEvent{
+		{1, "attempt 1 failed", 10 * time.Millisecond},
+		{2, "attempt 2 failed", 20 * time.Millisecond},
+	}, events)
+}
+
+func TestAsyncUtil_Retry_Panic(t *testing.T) {
+	calls := 0
+	val, err := Retry(context.Background(), RetryPolicy{Clock: newInstantClock()}, func(ctx context.Context) (int, error) {
+		calls++
+		if calls == 1 {
+			panic("retry boom")
+		}
+		return 7, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, val)
+	assert.Equal(t, 2, calls)
+}
+
+func TestAsyncUtil_Retry_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	policy := RetryPolicy{MaxAttempts: 5, InitialDelay: time.Hour, Jitter: noJitter}
+
+	calls := 0
+	_, err := Retry(ctx, policy, func(ctx context.Context) (int, error) {
+		calls++
+		cancel()
+		return 0, errors.New("unavailable")
+	})
+
+	assert.EqualError(t, err, "unavailable")
+	assert.Equal(t, 1, calls)
+
+	t.Run("while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := Retry(ctx, policy, func(ctx context.Context) (int, error) {
+			return 0, errors.New("unavailable")
+		})
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestAsyncUtil_IsRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"generic error", errors.New("timeout talking to upstream"), true},
+		{"internal server error", apperror.Err500InternalServer, true},
+		{"invalid data", apperror.Err400InvalidData, false},
+		{"unauthorized", apperror.Err401Unauthorized, false},
+		{"wrapped not found", fmt.Errorf("get user: %w", apperror.Err404RecordNotFound), false},
+		{"context canceled", context.Canceled, false},
+		{"deadline exceeded", context.DeadlineExceeded, false},
+		{"permanent", Permanent(errors.New("bad config")), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestAsyncUtil_fullJitter(t *testing.T) {
+	for range 100 {
+		d := fullJitter(100 * time.Millisecond)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.LessOrEqual(t, d, 100*time.Millisecond)
+	}
+
+	assert.Equal(t, time.Duration(0), fullJitter(0))
+	assert.GreaterOrEqual(t, fullJitter(math.MaxInt64), time.Duration(0))
+	assert.Nil(t, Permanent(nil))
+}
+
+func BenchmarkAsyncUtil_Retry(b *testing.B) {
+	policy := RetryPolicy{Clock: newInstantClock()}
+	ctx := context.Background()
+
+	for b.Loop() {
+		calls := 0
+		Retry(ctx, policy, func(ctx context.Context) (int, error) {
+			calls++
+			if calls < 2 {
+				return 0, errors.New("flaky")
+			}
+			return calls, nil
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrSchedulerStopped is returned when adding a job to a stopped Scheduler.
+	ErrSchedulerStopped = errors.New("asyncutil: scheduler is stopped")
+
+	// ErrJobExists is returned when adding a job whose name is already taken.
+	ErrJobExists = errors.New("asyncutil: job already exists")
+)
+
+// OverlapPolicy decides what happens when a job is due while its previous run
+// is still going.
+type OverlapPolicy int
+
+const (
+	// OverlapSkip drops the run that is due.
+	OverlapSkip OverlapPolicy = iota
+
+	// OverlapQueue starts the run as soon as the previous one finishes. At most
+	// one run is queued; further due runs are skipped while one is waiting.
+	OverlapQueue
+
+	// OverlapAllow starts the run right away, next to the previous one.
+	OverlapAllow
+)
+
+// JobConfig describes a scheduled job.
+type JobConfig struct {
+	// Name identifies the job in Status and is set as the task name of the job
+	// context, see WithTaskName. Required and unique per Scheduler.
+	Name string
+
+	// Schedule decides when the job runs, e.g. Every(time.Minute) or
+	// MustParseCron("0 3 * * *", time.UTC). Required.
+	Schedule Schedule
+
+	// Overlap decides what happens when a run is due while the previous one is
+	// still going. Defaults to OverlapSkip.
+	Overlap OverlapPolicy
+
+	// Jitter delays every run by a random duration in [0, Jitter), so replicas
+	// of a service do not all hit a dependency at the same instant.
+	Jitter time.Duration
+}
+
+// JobStatus is a snapshot of a job, returned by Scheduler.Status.
+type JobStatus struct {
+	Name         string
+	Running      int
+	Queued       bool
+	Runs         int
+	Failures     int
+	Skipped      int
+	LastStart    time.Time
+	LastDuration time.Duration
+	LastError    error
+	NextRun      time.Time
+}
+
+// SchedulerConfig configures a Scheduler.
+type SchedulerConfig struct {
+	// OnError is called when a run returns an error or panics.
+	OnError func(job string, err error)
+
+	// Clock is used to wait for runs. Defaults to RealClock.
+	Clock Clock
+}
+
+// Scheduler runs jobs on interval or cron schedules. Runs are protected the
+// same way as SafeGo: panics are recovered, reported to OnPanic and recorded
+// as failures, so a panicking job keeps its schedule.
+//
+// Example:
+//
+//	scheduler := NewScheduler(SchedulerConfig{
+//	    OnError: func(job string, err error) {
+//	        slog.Error("scheduled job failed", "job", job, "err", err)
+//	    },
+//	})
+//
+//	scheduler.Add(JobConfig{
+//	    Name:     "purge-sessions",
+//	    Schedule: MustParseCron("*/15 * * * *", time.UTC),
+//	    Jitter:   30 * time.Second,
+//	}, func(ctx context.Context) error {
+//	    return repo.PurgeExpiredSessions(ctx)
+//	})
+//
+//	// On shutdown, let running jobs finish for up to 10 seconds.
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	scheduler.Stop(ctx)
+type Scheduler struct {
+	cfg    SchedulerConfig
+	clock  Clock
+	ctx    context.Context
+	cancel context.CancelFunc
+	quit   chan struct{}
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	jobs    map[string]*scheduledJob
+	stopped bool
+}
+
+type scheduledJob struct {
+	cfg JobConfig
+	fn  func(ctx context.Context) error
+
+	mu     sync.Mutex
+	status JobStatus
+}
+
+// NewScheduler returns a Scheduler with no jobs.
+func NewScheduler(cfg SchedulerConfig) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Scheduler{
+		cfg:    cfg,
+		clock:  clockOrReal(cfg.Clock),
+		ctx:    ctx,
+		cancel: cancel,
+		quit:   make(chan struct{}),
+		jobs:   make(map[string]*scheduledJob),
+	}
+}
+
+// Add registers a job and starts scheduling it right away. fn receives a
+// context that is cancelled when Stop gives up waiting.
+func (s *Scheduler) Add(cfg JobConfig, fn func(ctx context.Context) error) error {
+	if strings.TrimSpace(cfg.Name) == "" {
+		return errors.New("asyncutil: job name is required")
+	}
+	if cfg.Schedule == nil {
+		return fmt.Errorf("asyncutil: job %q has no schedule", cfg.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return ErrSchedulerStopped
+	}
+	if _, ok := s.jobs[cfg.Name]; ok {
+		return fmt.Errorf("%w: %q", ErrJobExists, cfg.Name)
+	}
+
+	job := &scheduledJob{cfg: cfg, fn: fn, status: JobStatus{Name: cfg.Name}}
+	s.jobs[cfg.Name] = job
+
+	s.wg.Add(1)
+	go s.schedule(job)
+
+	return nil
+}
+
+// Status returns a snapshot of the named job.
+func (s *Scheduler) Status(name string) (JobStatus, bool) {
+	s.mu.Lock()
+	job, ok := s.jobs[name]
+	s.mu.Unlock()
+
+	if !ok {
+		return JobStatus{}, false
+	}
+
+	job.mu.Lock()
+	defer job.mu.Unlock()
+
+	return job.status, true
+}
+
+// Statuses returns a snapshot of every job, sorted by name.
+func (s *Scheduler) Statuses() []JobStatus {
+	s.mu.Lock()
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	s.mu.Unlock()
+
+	slices.Sort(names)
+
+	statuses := make([]JobStatus, 0, len(names))
+	for _, name := range names {
+		if st, ok := s.Status(name); ok {
+			statuses = append(statuses, st)
+		}
+	}
+
+	return statuses
+}
+
+// Stop stops scheduling new runs and waits for running ones to finish. If ctx
+// is done first, the job contexts are cancelled and ctx.Err() is returned.
+// Calling Stop again waits the same way.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.quit)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) isStopping() bool {
+	select {
+	case <-s.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// schedule waits for every due time of job and triggers a run.
+func (s *Scheduler) schedule(job *scheduledJob) {
+	defer s.wg.Done()
+
+	for {
+		now := s.clock.Now()
+		next := job.cfg.Schedule.Next(now)
+		if next.IsZero() {
+			return
+		}
+
+		if job.cfg.Jitter > 0 {
+			next = next.Add(rand.N(job.cfg.Jitter))
+		}
+
+		job.mu.Lock()
+		job.status.NextRun = next
+		job.mu.Unlock()
+
+		select {
+		case <-s.clock.After(next.Sub(now)):
+		case <-s.quit:
+			return
+		}
+
+		s.trigger(job)
+	}
+}
+
+// trigger starts a run of job according to its overlap policy.
+func (s *Scheduler) trigger(job *scheduledJob) {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+
+	if job.status.Running > 0 {
+		switch job.cfg.Overlap {
+		case OverlapSkip:
+			job.status.Skipped++
+			return
+		case OverlapQueue:
+			if job.status.Queued {
+				job.status.Skipped++
+			}
+			job.status.Queued = true
+			return
+		}
+	}
+
+	job.status.Running++
+
+	s.wg.Add(1)
+	go s.run(job)
+}
+
+// run executes job, then any run queued meanwhile.
+func (s *Scheduler) run(job *scheduledJob) {
+	defer s.wg.Done()
+
+	ctx := WithTaskName(s.ctx, job.cfg.Name)
+
+	for {
+		start := s.clock.Now()
+		job.mu.Lock()
+		job.status.LastStart = start
+		job.mu.Unlock()
+
+		_, err := runTask(ctx, func() (struct{}, error) {
+			return struct{}{}, job.fn(ctx)
+		})
+
+		job.mu.Lock()
+		job.status.Runs++
+		job.status.LastDuration = s.clock.Now().Sub(start)
+		job.status.LastError = err
+		if err != nil {
+			job.status.Failures++
+		}
+
+		queued := job.status.Queued
+		job.status.Queued = false
+		if queued && s.isStopping() {
+			queued = false
+		}
+		if !queued {
+			job.status.Running--
+		}
+		job.mu.Unlock()
+
+		if err != nil && s.cfg.OnError != nil {
+			s.cfg.OnError(job.cfg.Name, err)
+		}
+
+		if !queued {
+			return
+		}
+	}
+}
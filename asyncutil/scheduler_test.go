@@ -0,0 +1,264 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSchedulerClock() *FakeClock {
+	return NewFakeClock(time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC))
+}
+
+// tick advances clock by d once the scheduler waits on it.
+func tick(t *testing.T, clock *FakeClock, d time.Duration) {
+	t.Helper()
+	waitForWaiters(t, clock, 1)
+	clock.Advance(d)
+}
+
+func TestAsyncUtil_Scheduler_Add(t *testing.T) {
+	s := NewScheduler(SchedulerConfig{Clock: newSchedulerClock()})
+	defer s.Stop(context.Background())
+
+	noop := func(ctx context.Context) error { return nil }
+
+	tests := []struct {
+		name        string
+		cfg         JobConfig
+		expectedErr error
+		errContains string
+	}{
+		{name: "valid", cfg: JobConfig{Name: "cleanup", Schedule: Every(time.Minute)}},
+		{name: "duplicate name", cfg: JobConfig{Name: "cleanup", Schedule: Every(time.Minute)}, expectedErr: ErrJobExists},
+		{name: "missing name", cfg: JobConfig{Schedule: Every(time.Minute)}, errContains: "job name is required"},
+		{name: "missing schedule", cfg: JobConfig{Name: "report"}, errContains: `job "report" has no schedule`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Add(tt.cfg, noop)
+
+			switch {
+			case tt.expectedErr != nil:
+				assert.ErrorIs(t, err, tt.expectedErr)
+			case tt.errContains != "":
+				assert.ErrorContains(t, err, tt.errContains)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAsyncUtil_Scheduler_RunsOnSchedule(t *testing.T) {
+	clock := newSchedulerClock()
+	s := NewScheduler(SchedulerConfig{Clock: clock})
+
+	runs := make(chan string, 10)
+	require.NoError(t, s.Add(JobConfig{
+		Name:     "report",
+		Schedule: MustParseCron("*/15 * * * *", time.UTC),
+	}, func(ctx context.Context) error {
+		runs <- TaskName(ctx)
+		return nil
+	}))
+
+	waitForWaiters(t, clock, 1)
+	st, _ := s.Status("report")
+	assert.Equal(t, time.Date(2026, 1, 15, 10, 15, 0, 0, time.UTC), st.NextRun)
+
+	clock.Advance(15 * time.Minute)
+	assert.Equal(t, "report", <-runs)
+
+	tick(t, clock, 15*time.Minute)
+	assert.Equal(t, "report", <-runs)
+
+	require.NoError(t, s.Stop(context.Background()))
+
+	st, ok := s.Status("report")
+	require.True(t, ok)
+	assert.Equal(t, 2, st.Runs)
+	assert.Equal(t, 0, st.Running)
+	assert.Equal(t, time.Date(2026, 1, 15, 10, 45, 0, 0, time.UTC), st.NextRun)
+
+	assert.ErrorIs(t, s.Add(JobConfig{Name: "late", Schedule: Every(time.Second)}, nil), ErrSchedulerStopped)
+}
+
+func TestAsyncUtil_Scheduler_Overlap(t *testing.T) {
+	tests := []struct {
+		name            string
+		policy          OverlapPolicy
+		expectedRuns    int
+		expectedSkipped int
+		maxConcurrent   int32
+	}{
+		{name: "skip", policy: OverlapSkip, expectedRuns: 1, expectedSkipped: 2, maxConcurrent: 1},
+		{name: "queue", policy: OverlapQueue, expectedRuns: 2, expectedSkipped: 1, maxConcurrent: 1},
+		{name: "allow", policy: OverlapAllow, expectedRuns: 3, expectedSkipped: 0, maxConcurrent: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newSchedulerClock()
+			s := NewScheduler(SchedulerConfig{Clock: clock})
+
+			release := make(chan struct{})
+			var running, peak atomic.Int32
+
+			require.NoError(t, s.Add(JobConfig{
+				Name:     "slow",
+				Schedule: Every(time.Minute),
+				Overlap:  tt.policy,
+			}, func(ctx context.Context) error {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				<-release
+				running.Add(-1)
+				return nil
+			}))
+
+			// Three due times while the first run is blocked.
+			for range 3 {
+				tick(t, clock, time.Minute)
+			}
+			waitForWaiters(t, clock, 1)
+			assert.Eventually(t, func() bool {
+				return running.Load() == tt.maxConcurrent
+			}, time.Second, time.Millisecond)
+
+			close(release)
+			assert.Eventually(t, func() bool {
+				st, _ := s.Status("slow")
+				return st.Runs == tt.expectedRuns && st.Running == 0
+			}, time.Second, time.Millisecond)
+			require.NoError(t, s.Stop(context.Background()))
+
+			st, _ := s.Status("slow")
+			assert.Equal(t, tt.expectedRuns, st.Runs)
+			assert.Equal(t, tt.expectedSkipped, st.Skipped)
+			assert.Equal(t, tt.maxConcurrent, peak.Load())
+		})
+	}
+}
+
+func TestAsyncUtil_Scheduler_FailuresAndPanics(t *testing.T) {
+	clock := newSchedulerClock()
+	errFailed := errors.New("cleanup failed")
+
+	errs := make(chan error, 10)
+	s := NewScheduler(SchedulerConfig{
+		Clock:   clock,
+		OnError: func(job string, err error) { errs <- err },
+	})
+
+	var calls atomic.Int32
+	require.NoError(t, s.Add(JobConfig{Name: "flaky", Schedule: Every(time.Minute)}, func(ctx context.Context) error {
+		if calls.Add(1) == 1 {
+			return errFailed
+		}
+		panic("job boom")
+	}))
+
+	tick(t, clock, time.Minute)
+	assert.ErrorIs(t, <-errs, errFailed)
+
+	// The job keeps its schedule after a panic.
+	tick(t, clock, time.Minute)
+	err := <-errs
+	var perr *PanicError
+	require.ErrorAs(t, err, &perr)
+	assert.Equal(t, "flaky", perr.Task)
+
+	tick(t, clock, time.Minute)
+	<-errs
+
+	require.NoError(t, s.Stop(context.Background()))
+
+	st, _ := s.Status("flaky")
+	assert.Equal(t, 3, st.Runs)
+	assert.Equal(t, 3, st.Failures)
+	assert.ErrorAs(t, st.LastError, &perr)
+}
+
+func TestAsyncUtil_Scheduler_Jitter(t *testing.T) {
+	clock := newSchedulerClock()
+	s := NewScheduler(SchedulerConfig{Clock: clock})
+	defer s.Stop(context.Background())
+
+	require.NoError(t, s.Add(JobConfig{
+		Name:     "jittered",
+		Schedule: Every(time.Minute),
+		Jitter:   10 * time.Second,
+	}, func(ctx context.Context) error { return nil }))
+
+	waitForWaiters(t, clock, 1)
+	st, _ := s.Status("jittered")
+
+	delay := st.NextRun.Sub(clock.Now())
+	assert.GreaterOrEqual(t, delay, time.Minute)
+	assert.Less(t, delay, time.Minute+10*time.Second)
+}
+
+func TestAsyncUtil_Scheduler_StopTimeout(t *testing.T) {
+	clock := newSchedulerClock()
+	s := NewScheduler(SchedulerConfig{Clock: clock})
+
+	cancelled := make(chan struct{})
+	require.NoError(t, s.Add(JobConfig{Name: "stuck", Schedule: Every(time.Minute)}, func(ctx context.Context) error {
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	}))
+
+	tick(t, clock, time.Minute)
+	assert.Eventually(t, func() bool {
+		st, _ := s.Status("stuck")
+		return st.Running == 1
+	}, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, s.Stop(ctx), context.DeadlineExceeded)
+	<-cancelled
+	assert.NoError(t, s.Stop(context.Background()))
+}
+
+func TestAsyncUtil_Scheduler_Statuses(t *testing.T) {
+	s := NewScheduler(SchedulerConfig{Clock: newSchedulerClock()})
+	defer s.Stop(context.Background())
+
+	for _, name := range []string{"b-job", "a-job"} {
+		require.NoError(t, s.Add(JobConfig{Name: name, Schedule: Every(time.Hour)}, func(ctx context.Context) error { return nil }))
+	}
+
+	statuses := s.Statuses()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "a-job", statuses[0].Name)
+	assert.Equal(t, "b-job", statuses[1].Name)
+
+	_, ok := s.Status("missing")
+	assert.False(t, ok)
+}
+
+func BenchmarkAsyncUtil_Scheduler_Status(b *testing.B) {
+	s := NewScheduler(SchedulerConfig{Clock: newSchedulerClock()})
+	defer s.Stop(context.Background())
+
+	s.Add(JobConfig{Name: "job", Schedule: Every(time.Hour)}, func(ctx context.Context) error { return nil })
+
+	for b.Loop() {
+		s.Status("job")
+	}
+}
@@ -0,0 +1,107 @@
+package asyncutil
+
+import (
+	"context"
+	"sync"
+)
+
+// SingleFlight deduplicates concurrent calls by key: while a call for a key is
+// in flight, other callers with the same key wait for it and share its result.
+// Panics are recovered like SafeGo and returned to every caller as *PanicError.
+//
+// The zero value is ready to use.
+//
+// Example:
+//
+//	var users SingleFlight[string, User]
+//
+//	// 50 concurrent requests for the same id hit the database once.
+//	user, err, _ := users.DoCtx(ctx, id, func(ctx context.Context) (User, error) {
+//	    return repo.FindUser(ctx, id)
+//	})
+type SingleFlight[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*flightCall[V]
+}
+
+type flightCall[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+	dups int
+}
+
+// Do calls fn for key unless a call for key is already in flight, in which
+// case it waits for that call. shared reports whether the result was given
+// to more than one caller.
+func (s *SingleFlight[K, V]) Do(key K, fn func() (V, error)) (val V, err error, shared bool) {
+	c, leader := s.join(key)
+	if leader {
+		s.run(context.Background(), key, c, fn)
+	}
+
+	<-c.done
+	return c.val, c.err, c.dups > 0
+}
+
+// DoCtx is the context-aware form of Do. A caller whose context is done stops
+// waiting and gets ctx.Err(), but the shared call keeps running for the other
+// callers: fn receives a context that carries the values of the first caller's
+// context without its cancellation.
+func (s *SingleFlight[K, V]) DoCtx(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) (val V, err error, shared bool) {
+	c, leader := s.join(key)
+	if leader {
+		callCtx := context.WithoutCancel(ctx)
+		go s.run(callCtx, key, c, func() (V, error) { return fn(callCtx) })
+	}
+
+	select {
+	case <-c.done:
+		return c.val, c.err, c.dups > 0
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err(), !leader
+	}
+}
+
+// Forget makes the next call for key start a new call instead of waiting for
+// the one in flight.
+func (s *SingleFlight[K, V]) Forget(key K) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.calls, key)
+}
+
+// join returns the in-flight call for key, or registers a new one and reports
+// that the caller leads it.
+func (s *SingleFlight[K, V]) join(key K) (*flightCall[V], bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.calls == nil {
+		s.calls = make(map[K]*flightCall[V])
+	}
+
+	if c, ok := s.calls[key]; ok {
+		c.dups++
+		return c, false
+	}
+
+	c := &flightCall[V]{done: make(chan struct{})}
+	s.calls[key] = c
+
+	return c, true
+}
+
+func (s *SingleFlight[K, V]) run(ctx context.Context, key K, c *flightCall[V], fn func() (V, error)) {
+	c.val, c.err = safeCall(ctx, fn)
+
+	s.mu.Lock()
+	if s.calls[key] == c {
+		delete(s.calls, key)
+	}
+	s.mu.Unlock()
+
+	close(c.done)
+}
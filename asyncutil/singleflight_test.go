@@ -0,0 +1,188 @@
+package asyncutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsyncUtil_SingleFlight_Do(t *testing.T) {
+	errLoad := errors.New("load failed")
+
+	tests := []struct {
+		name        string
+		fn          func() (string, error)
+		expected    string
+		expectedErr error
+		errContains string
+	}{
+		{
+			name:     "value",
+			fn:       func() (string, error) { return "alice", nil },
+			expected: "alice",
+		},
+		{
+			name:        "error",
+			fn:          func() (string, error) { return "", errLoad },
+			expectedErr: errLoad,
+		},
+		{
+			name:        "panic",
+			fn:          func() (string, error) { panic("flight boom") },
+			errContains: "panic recovered: flight boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sf SingleFlight[string, string]
+
+			val, err, shared := sf.Do("user:1", tt.fn)
+
+			assert.Equal(t, tt.expected, val)
+			assert.False(t, shared)
+			switch {
+			case tt.expectedErr != nil:
+				assert.ErrorIs(t, err, tt.expectedErr)
+			case tt.errContains != "":
+				var perr *PanicError
+				assert.ErrorAs(t, err, &perr)
+				assert.ErrorContains(t, err, tt.errContains)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAsyncUtil_SingleFlight_Dedup(t *testing.T) {
+	var sf SingleFlight[string, int]
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+	const callers = 20
+
+	var wg sync.WaitGroup
+	var sharedCount atomic.Int32
+	results := make([]int, callers)
+
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err, shared := sf.Do("hot", func() (int, error) {
+				calls.Add(1)
+				<-release
+				return 42, nil
+			})
+			assert.NoError(t, err)
+			results[i] = val
+			if shared {
+				sharedCount.Add(1)
+			}
+		}()
+	}
+
+	// Let every caller join the flight before it completes.
+	assert.Eventually(t, func() bool {
+		sf.mu.Lock()
+		defer sf.mu.Unlock()
+		c, ok := sf.calls["hot"]
+		return ok && c.dups == callers-1
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, int32(callers), sharedCount.Load())
+	for _, r := range results {
+		assert.Equal(t, 42, r)
+	}
+
+	// Once finished, the next call runs again.
+	val, _, shared := sf.Do("hot", func() (int, error) { return 7, nil })
+	assert.Equal(t, 7, val)
+	assert.False(t, shared)
+}
+
+func TestAsyncUtil_SingleFlight_DoCtx(t *testing.T) {
+	var sf SingleFlight[string, string]
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	type ctxKey struct{}
+	leaderCtx, cancelLeader := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "trace-1"))
+
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, err, _ := sf.DoCtx(leaderCtx, "k", func(ctx context.Context) (string, error) {
+			close(started)
+			<-release
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			return ctx.Value(ctxKey{}).(string), nil
+		})
+		leaderDone <- err
+	}()
+	<-started
+
+	follower := make(chan Result[string], 1)
+	go func() {
+		val, err, _ := sf.DoCtx(context.Background(), "k", func(ctx context.Context) (string, error) {
+			return "not called", nil
+		})
+		follower <- Result[string]{Value: val, Err: err}
+	}()
+
+	assert.Eventually(t, func() bool {
+		sf.mu.Lock()
+		defer sf.mu.Unlock()
+		return sf.calls["k"].dups == 1
+	}, time.Second, time.Millisecond)
+
+	// The first caller gives up, the shared call keeps running for the follower.
+	cancelLeader()
+	assert.ErrorIs(t, <-leaderDone, context.Canceled)
+
+	close(release)
+	res := <-follower
+	require.NoError(t, res.Err)
+	assert.Equal(t, "trace-1", res.Value)
+}
+
+func TestAsyncUtil_SingleFlight_Forget(t *testing.T) {
+	var sf SingleFlight[string, int]
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	go sf.Do("k", func() (int, error) {
+		close(started)
+		<-release
+		return 1, nil
+	})
+	<-started
+
+	sf.Forget("k")
+	val, _, shared := sf.Do("k", func() (int, error) { return 2, nil })
+	close(release)
+
+	assert.Equal(t, 2, val)
+	assert.False(t, shared)
+}
+
+func BenchmarkAsyncUtil_SingleFlight_Do(b *testing.B) {
+	var sf SingleFlight[int, int]
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			sf.Do(1, func() (int, error) { return 1, nil })
+		}
+	})
+}
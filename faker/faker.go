@@ -0,0 +1,162 @@
+package faker
+
+import (
+	"math"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// SeedEnv is the environment variable read by ForTest to replay a seed.
+const SeedEnv = "FAKER_SEED"
+
+// Faker generates random data from its own seeded source, so the same seed
+// always produces the same sequence of values. It is safe for concurrent use,
+// although values are only reproducible when calls happen in the same order.
+//
+// Example:
+//
+//	f := faker.New(42)
+//	email := f.RandEmail() // same email on every run
+type Faker struct {
+	seed         uint64
+	cycleLimit   int
+	validateTags bool
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// New returns a Faker seeded with seed.
+func New(seed uint64) *Faker {
+	return &Faker{
+		seed:       seed,
+		cycleLimit: 1,
+		rng:        rand.New(rand.NewPCG(seed, seed)),
+	}
+}
+
+// Seed returns the seed the Faker was created with.
+func (f *Faker) Seed() uint64 {
+	return f.seed
+}
+
+// SetCycleLimit sets how many times GenerateFakeWith nests a struct type inside
+// itself, e.g. through a `Parent *Category` field. Deeper pointers are left nil,
+// and slices and maps get no elements of that type.
+// Defaults to 1. It must be called before the Faker is used.
+func (f *Faker) SetCycleLimit(n int) {
+	f.cycleLimit = max(n, 0)
+}
+
+func (f *Faker) intN(n int) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.rng.IntN(n)
+}
+
+func (f *Faker) int64N(n int64) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.rng.Int64N(n)
+}
+
+// uint64N returns a random number in [0, n], so the full uint64 range can be drawn.
+func (f *Faker) uint64N(n uint64) uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if n == math.MaxUint64 {
+		return f.rng.Uint64()
+	}
+	return f.rng.Uint64N(n + 1)
+}
+
+func (f *Faker) float64() float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.rng.Float64()
+}
+
+// Read fills p with random bytes, so a Faker can be used as an io.Reader.
+func (f *Faker) Read(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := range p {
+		p[i] = byte(f.rng.Uint32())
+	}
+	return len(p), nil
+}
+
+var defaultFaker atomic.Pointer[Faker]
+
+func init() {
+	defaultFaker.Store(New(rand.Uint64()))
+}
+
+// Default returns the Faker used by the package-level functions. It is seeded
+// randomly at startup.
+func Default() *Faker {
+	return defaultFaker.Load()
+}
+
+// SetDefault replaces the Faker used by the package-level functions, e.g. to
+// make them reproducible in a test.
+//
+// Example:
+//
+//	faker.SetDefault(faker.New(42))
+func SetDefault(f *Faker) {
+	defaultFaker.Store(f)
+}
+
+// TB is the part of testing.TB used by ForTest.
+type TB interface {
+	Helper()
+	Cleanup(func())
+	Failed() bool
+	Logf(format string, args ...any)
+}
+
+// ForTest returns a Faker for a test and logs its seed if the test fails. The
+// seed is read from the FAKER_SEED environment variable when set, so a failure
+// can be replayed, and is random otherwise.
+//
+// Example:
+//
+//	func TestCreateUser(t *testing.T) {
+//	    f := faker.ForTest(t)
+//	    user := faker.GenerateFakeWith[User](f)
+//	    ...
+//	}
+//
+//	// On failure the test log shows:
+//	//   faker seed: 8053312342 (rerun with FAKER_SEED=8053312342)
+func ForTest(t TB) *Faker {
+	t.Helper()
+
+	seed := rand.Uint64()
+	if env := os.Getenv(SeedEnv); env != "" {
+		s, err := strconv.ParseUint(env, 10, 64)
+		if err != nil {
+			t.Logf("faker: ignoring invalid %s %q", SeedEnv, env)
+		} else {
+			seed = s
+		}
+	}
+
+	f := New(seed)
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("faker seed: %d (rerun with %s=%d)", seed, SeedEnv, seed)
+		}
+	})
+
+	return f
+}
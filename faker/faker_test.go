@@ -0,0 +1,189 @@
+package faker_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shoraid/stx-go-utils/faker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTB records what ForTest does with the test.
+type fakeTB struct {
+	failed   bool
+	cleanups []func()
+	logs     []string
+}
+
+func (tb *fakeTB) Helper()                 {}
+func (tb *fakeTB) Cleanup(fn func())       { tb.cleanups = append(tb.cleanups, fn) }
+func (tb *fakeTB) Failed() bool            { return tb.failed }
+func (tb *fakeTB) Logf(f string, a ...any) { tb.logs = append(tb.logs, fmt.Sprintf(f, a...)) }
+
+func (tb *fakeTB) finish() {
+	for _, fn := range tb.cleanups {
+		fn()
+	}
+}
+
+func TestFaker_New_Deterministic(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	tests := []struct {
+		name     string
+		generate func(f *faker.Faker) any
+	}{
+		{name: "PickRandom", generate: func(f *faker.Faker) any { return f.PickRandom("a", "b", "c", "d", "e") }},
+		{name: "RandBool", generate: func(f *faker.Faker) any { return f.RandBool() }},
+		{name: "RandEmail", generate: func(f *faker.Faker) any { return f.RandEmail() }},
+		{name: "RandInt", generate: func(f *faker.Faker) any { return f.RandInt(0, 1_000_000) }},
+		{name: "RandSentence", generate: func(f *faker.Faker) any { return f.RandSentence(5) }},
+		{name: "RandString", generate: func(f *faker.Faker) any { return f.RandString(16) }},
+		{name: "RandTime", generate: func(f *faker.Faker) any { return f.RandTime(start, end) }},
+		{name: "RandURL", generate: func(f *faker.Faker) any { return f.RandURL() }},
+		{name: "UUID", generate: func(f *faker.Faker) any { return f.UUID() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := faker.New(42), faker.New(42)
+
+			var fromA, fromB []any
+			for range 20 {
+				fromA = append(fromA, tt.generate(a))
+				fromB = append(fromB, tt.generate(b))
+			}
+
+			assert.Equal(t, fromA, fromB, "same seed should produce the same sequence")
+		})
+	}
+}
+
+func TestFaker_New_DifferentSeeds(t *testing.T) {
+	a, b := faker.New(1), faker.New(2)
+
+	assert.NotEqual(t, a.RandString(32), b.RandString(32))
+	assert.Equal(t, uint64(1), a.Seed())
+	assert.Equal(t, uint64(2), b.Seed())
+}
+
+func TestFaker_RandString_NoCollisions(t *testing.T) {
+	seen := make(map[string]bool)
+	for range 1000 {
+		s := faker.RandString(16)
+		assert.False(t, seen[s], "RandString should not repeat in a tight loop")
+		seen[s] = true
+	}
+}
+
+func TestFaker_ConcurrentUse(t *testing.T) {
+	f := faker.New(7)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				f.RandEmail()
+				f.RandString(8)
+				f.UUID()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestFaker_SetDefault(t *testing.T) {
+	original := faker.Default()
+	defer faker.SetDefault(original)
+
+	faker.SetDefault(faker.New(99))
+	first := []any{faker.RandString(10), faker.RandInt(0, 1000), faker.RandEmail()}
+
+	faker.SetDefault(faker.New(99))
+	second := []any{faker.RandString(10), faker.RandInt(0, 1000), faker.RandEmail()}
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, uint64(99), faker.Default().Seed())
+}
+
+func TestFaker_ForTest(t *testing.T) {
+	tests := []struct {
+		name         string
+		env          string
+		failed       bool
+		expectedSeed uint64
+		expectedLogs []string
+	}{
+		{
+			name:         "seed from environment",
+			env:          "1234",
+			expectedSeed: 1234,
+		},
+		{
+			name:         "logs seed on failure",
+			env:          "1234",
+			failed:       true,
+			expectedSeed: 1234,
+			expectedLogs: []string{"faker seed: 1234 (rerun with FAKER_SEED=1234)"},
+		},
+		{
+			name:         "invalid environment value",
+			env:          "abc",
+			expectedLogs: []string{`faker: ignoring invalid FAKER_SEED "abc"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(faker.SeedEnv, tt.env)
+
+			tb := &fakeTB{}
+			f := faker.ForTest(tb)
+			tb.failed = tt.failed
+			tb.finish()
+
+			if tt.expectedSeed != 0 {
+				assert.Equal(t, tt.expectedSeed, f.Seed())
+			}
+			assert.Equal(t, tt.expectedLogs, tb.logs)
+		})
+	}
+}
+
+func TestFaker_ForTest_RandomSeed(t *testing.T) {
+	t.Setenv(faker.SeedEnv, "")
+
+	a := faker.ForTest(t)
+	b := faker.ForTest(t)
+	require.NotEqual(t, a.Seed(), b.Seed())
+}
+
+func TestFaker_GenerateFakeWith(t *testing.T) {
+	a := faker.GenerateFakeWith[TestStruct](faker.New(5))
+	b := faker.GenerateFakeWith[TestStruct](faker.New(5))
+
+	assert.Equal(t, a.Name, b.Name)
+	assert.Equal(t, *a.Bio, *b.Bio)
+	assert.Equal(t, a.Age, b.Age)
+	assert.Equal(t, a.IsActive, b.IsActive)
+}
+
+func BenchmarkFaker_New(b *testing.B) {
+	for b.Loop() {
+		faker.New(42)
+	}
+}
+
+func BenchmarkFaker_Method_RandString(b *testing.B) {
+	f := faker.New(42)
+
+	for b.Loop() {
+		f.RandString(16)
+	}
+}
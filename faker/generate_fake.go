@@ -1,69 +1,700 @@
 package faker
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// defaultLen is the number of elements put in slices and maps whose tag has no len option.
+const defaultLen = 3
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// kindOptions lists every kind and the options it accepts besides len.
+var kindOptions = map[string][]string{
+	"string":   nil,
+	"sentence": nil,
+	"uuid_str": nil,
+	"email":    nil,
+	"url":      nil,
+	"bool":     nil,
+	"int":      {"min", "max"},
+	"float":    {"min", "max"},
+	"time":     {"past", "future"},
+}
+
+// TagError reports a `faker` tag, or a `validate` tag read for SetValidateTags,
+// that GenerateFake cannot apply.
+type TagError struct {
+	// Field is the path of the field from the generated type, e.g. "User.Address.City".
+	Field string
+	Tag   string
+	Err   error
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("faker: field %s: tag %q: %v", e.Field, e.Tag, e.Err)
+}
+
+func (e *TagError) Unwrap() error {
+	return e.Err
+}
+
+// GenerateFake returns a new T filled with random data from the default Faker.
+//
+// Fields tagged `faker:"<kind>[,option=value...]"` get a random value of that kind:
+//
+//	string    alphanumeric string; len sets its length (default 20)
+//	sentence  random words; len sets the word count (default 2)
+//	uuid_str  UUID v7 string
+//	email     email address
+//	url       https URL
+//	bool      random boolean
+//	int       whole number in [min, max] (default [99, 9999]), for integer and float fields
+//	float     number with two decimals in [min, max] (default [99, 9999]), for float fields
+//	time      time in [now-past, now+future]; past and future take durations like
+//	          "12h" or "30d" and default to 0, or to future=30d when neither is set
+//	oneof     one of the listed values, e.g. `faker:"oneof=active|inactive"`,
+//	          for string, bool and numeric fields
+//
+// Slices, arrays and maps get elements of the tagged kind; for them len sets
+// the number of elements (default 3), e.g. `faker:"sentence,len=5"`. Map keys
+// get a random value of their own type. Pointers are allocated. Kinds and
+// types of your own are added with RegisterTag and RegisterType.
+//
+// Nested and embedded structs, and slices, maps and pointers of structs, are
+// filled recursively even without a tag. A struct type is nested inside itself
+// at most the Faker's cycle limit times, see SetCycleLimit. Fields tagged
+// `faker:"-"` and other untagged fields are left zero, unless the Faker reads
+// `validate` tags, see SetValidateTags.
+//
+// GenerateFake panics with a *TagError if a tag is unknown, malformed or does
+// not fit its field; use TryGenerateFake to get the error instead.
+//
+// Example:
+//
+//	type Order struct {
+//	    ID       string      `faker:"uuid_str"`
+//	    Status   string      `faker:"oneof=pending|paid|shipped"`
+//	    Quantity int         `faker:"int,min=1,max=10"`
+//	    Code     string      `faker:"string,len=8"`
+//	    PaidAt   time.Time   `faker:"time,past=30d"`
+//	    Lines    []OrderLine `faker:"len=5"`
+//	    Buyer    *Customer
+//	}
+//
+//	order := faker.GenerateFake[Order]()
 func GenerateFake[T any]() *T {
+	return GenerateFakeWith[T](Default())
+}
+
+// GenerateFakeWith is like GenerateFake but draws from f, so the result is
+// reproducible from f's seed.
+func GenerateFakeWith[T any](f *Faker) *T {
+	t, err := TryGenerateFake[T](f)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// TryGenerateFake is like GenerateFakeWith but returns a *TagError instead of
+// panicking when a tag cannot be applied.
+//
+// Example:
+//
+//	user, err := faker.TryGenerateFake[User](faker.Default())
+//	if err != nil {
+//	    t.Fatal(err) // faker: field User.Age: tag "int,min=x": min must be a number, got "x"
+//	}
+func TryGenerateFake[T any](f *Faker) (*T, error) {
 	t := new(T)
 	v := reflect.ValueOf(t).Elem()
-	tType := v.Type()
+
+	if v.Kind() == reflect.Struct {
+		g := newGenerator(f, v.Type())
+		g.validate = f.validateTags
+		if _, err := g.fill(v, newFakeTag("")); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+type fakeTag struct {
+	kind string
+	// minLen and maxLen bound the length, -1 when unset.
+	minLen, maxLen int
+	min, max       *float64
+	past           time.Duration
+	future         *time.Duration
+	oneof          []string
+
+	// elem is the tag of container elements, set from validate tags.
+	elem *fakeTag
+	// rules are the validate rules of the value, which GenerateInvalidFakes breaks.
+	rules     []validateRule
+	omitempty bool
+}
+
+func newFakeTag(kind string) fakeTag {
+	return fakeTag{kind: kind, minLen: -1, maxLen: -1}
+}
+
+func parseFakeTag(tag string) (fakeTag, error) {
+	ft := newFakeTag("")
+	var options []string
+
+	// The kind comes first and may be left out, as in "len=2".
+	for i, part := range strings.Split(tag, ",") {
+		name, value, isOption := strings.Cut(strings.TrimSpace(part), "=")
+
+		switch {
+		case !isOption && i == 0:
+			if _, ok := kindOptions[name]; !ok {
+				if _, ok := tagGenerator(name); !ok {
+					return ft, fmt.Errorf("unknown kind %q", name)
+				}
+			}
+			ft.kind = name
+		case !isOption:
+			return ft, fmt.Errorf("unexpected %q, options are written name=value", name)
+		case name == "oneof" && i == 0:
+			if value == "" {
+				return ft, errors.New("oneof needs at least one value")
+			}
+			ft.kind = "oneof"
+			ft.oneof = strings.Split(value, "|")
+		case name == "oneof":
+			return ft, errors.New("oneof must come first")
+		case name == "len":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return ft, fmt.Errorf("len must be a non-negative integer, got %q", value)
+			}
+			ft.minLen, ft.maxLen = n, n
+		case name == "min" || name == "max":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return ft, fmt.Errorf("%s must be a number, got %q", name, value)
+			}
+			if name == "min" {
+				ft.min = &n
+			} else {
+				ft.max = &n
+			}
+			options = append(options, name)
+		case name == "past" || name == "future":
+			d, err := parseFakeDuration(value)
+			if err != nil {
+				return ft, fmt.Errorf("%s must be a duration like 12h or 30d, got %q", name, value)
+			}
+			if name == "past" {
+				ft.past = d
+			} else {
+				ft.future = &d
+			}
+			options = append(options, name)
+		default:
+			return ft, fmt.Errorf("unknown option %q", name)
+		}
+	}
+
+	for _, name := range options {
+		if ft.kind == "" {
+			return ft, fmt.Errorf("option %s needs a kind", name)
+		}
+		if !slices.Contains(kindOptions[ft.kind], name) {
+			return ft, fmt.Errorf("option %s is not valid for kind %s", name, ft.kind)
+		}
+	}
+
+	if ft.min != nil && ft.max != nil && *ft.min > *ft.max {
+		return ft, fmt.Errorf("min %v is greater than max %v", *ft.min, *ft.max)
+	}
+
+	return ft, nil
+}
+
+// parseFakeDuration parses a time.Duration, also accepting a number of days like "30d".
+func parseFakeDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// generator fills one value, tracking the struct types on the current path to
+// stop cycles, and the field path for errors.
+type generator struct {
+	f     *Faker
+	depth map[reflect.Type]int
+	path  []string
+	// field is the struct field being filled, passed to registered tags.
+	field reflect.StructField
+
+	// validate fills fields without a faker tag from their validate tag.
+	validate bool
+	breaker  *breaker
+	// skip is above zero inside elements whose rules are never broken.
+	skip int
+}
+
+func newGenerator(f *Faker, t reflect.Type) *generator {
+	return &generator{f: f, depth: make(map[reflect.Type]int), path: []string{t.Name()}}
+}
+
+func (g *generator) fillStruct(v reflect.Value) error {
+	t := v.Type()
 
 	for i := range v.NumField() {
 		field := v.Field(i)
-		fieldType := tType.Field(i)
+		fieldType := t.Field(i)
 		tag := fieldType.Tag.Get("faker")
+		vtag := ""
+		if g.validate {
+			vtag = fieldType.Tag.Get("validate")
+		}
 
-		if !field.CanSet() || tag == "" {
+		if tag == "-" {
 			continue
 		}
+		if vtag == "-" {
+			vtag = ""
+		}
 
-		switch tag {
-		case "string":
-			val := RandString(20)
-			if field.Kind() == reflect.Ptr {
-				field.Set(reflect.ValueOf(&val))
-			} else {
-				field.SetString(val)
+		// Exported fields of an unexported embedded struct are still settable.
+		if !field.CanSet() && !(fieldType.Anonymous && field.Kind() == reflect.Struct) {
+			continue
+		}
+
+		if tag == "" && vtag == "" && !fillsUntagged(field.Type()) {
+			continue
+		}
+
+		parent := g.field
+		g.path, g.field = append(g.path, fieldType.Name), fieldType
+		err := g.fillField(field, tag, vtag)
+		g.path, g.field = g.path[:len(g.path)-1], parent
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fillField fills a struct field from its faker tag, or from its validate tag
+// when it has no faker tag.
+func (g *generator) fillField(v reflect.Value, tag, vtag string) error {
+	ft := newFakeTag("")
+	var err error
+
+	switch {
+	case tag != "":
+		if ft, err = parseFakeTag(tag); err != nil {
+			return g.tagError(tag, err)
+		}
+		// The faker tag decides the value, the validate rules can still be broken.
+		if vtag != "" {
+			ft.rules, ft.omitempty = breakableRules(parseValidateTag(vtag)[0])
+		}
+	case vtag != "":
+		tag = vtag
+		if ft, err = validateFakeTag(v.Type(), parseValidateTag(vtag)); err != nil {
+			return g.tagError(tag, err)
+		}
+	}
+
+	if _, err := g.fill(v, ft); err != nil {
+		// Errors of nested fields already carry their own path.
+		var tagErr *TagError
+		if errors.As(err, &tagErr) {
+			return err
+		}
+		return g.tagError(tag, err)
+	}
+
+	return nil
+}
+
+func (g *generator) tagError(tag string, err error) *TagError {
+	return &TagError{Field: strings.Join(g.path, "."), Tag: tag, Err: err}
+}
+
+// fill sets v from tag. It reports false, leaving v untouched, when a cycle
+// limit is reached. Slices and maps then skip the element, arrays leave it zero.
+func (g *generator) fill(v reflect.Value, tag fakeTag) (bool, error) {
+	ok, err := g.fillValue(v, tag)
+	if ok && err == nil && g.breaker != nil && len(tag.rules) > 0 {
+		g.breaker.visit(g, v, tag)
+	}
+	return ok, err
+}
+
+func (g *generator) fillValue(v reflect.Value, tag fakeTag) (bool, error) {
+	if gen, ok := typeGenerator(v.Type()); ok && tag.kind == "" {
+		v.Set(gen(g.f))
+		return true, nil
+	}
+
+	// len belongs to the container; its elements use their default length.
+	elemTag := tag
+	elemTag.minLen, elemTag.maxLen = -1, -1
+	elemTag.rules, elemTag.omitempty = nil, false
+	if tag.elem != nil {
+		elemTag = *tag.elem
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		// The rules apply to the pointer itself, not again to what it points to.
+		ptrTag := tag
+		ptrTag.rules = nil
+		elem := reflect.New(v.Type().Elem())
+		if ok, err := g.fill(elem.Elem(), ptrTag); !ok || err != nil {
+			return false, err
+		}
+		v.Set(elem)
+		return true, nil
+
+	case reflect.Slice:
+		n := g.length(tag, defaultLen)
+		s := reflect.MakeSlice(v.Type(), n, n)
+		filled := 0
+		for range n {
+			ok, err := g.fillElem(s.Index(filled), filled, elemTag)
+			if err != nil {
+				return false, err
 			}
-		case "sentence":
-			val := RandSentence(2)
-			if field.Kind() == reflect.Ptr {
-				field.Set(reflect.ValueOf(&val))
-			} else {
-				field.SetString(val)
+			if ok {
+				filled++
 			}
-		case "uuid_str":
-			val := UUID()
-			if field.Kind() == reflect.Ptr {
-				field.Set(reflect.ValueOf(&val))
-			} else {
-				field.Set(reflect.ValueOf(val))
+		}
+		v.Set(s.Slice(0, filled))
+		return true, nil
+
+	case reflect.Array:
+		arr := reflect.New(v.Type()).Elem()
+		for i := range arr.Len() {
+			if _, err := g.fillElem(arr.Index(i), i, elemTag); err != nil {
+				return false, err
 			}
-		case "bool":
-			val := RandBool()
-			if field.Kind() == reflect.Ptr {
-				field.Set(reflect.ValueOf(&val))
-			} else {
-				field.SetBool(val)
+		}
+		v.Set(arr)
+		return true, nil
+
+	case reflect.Map:
+		keyTag := newFakeTag(defaultKind(v.Type().Key()))
+		n := g.length(tag, defaultLen)
+		m := reflect.MakeMapWithSize(v.Type(), n)
+
+		// Map values have no stable path, so their rules are never broken.
+		g.skip++
+		defer func() { g.skip-- }()
+
+		for range n {
+			key := reflect.New(v.Type().Key()).Elem()
+			keyOK, err := g.fill(key, keyTag)
+			if err != nil {
+				return false, err
 			}
-		case "int":
-			val := RandInt(99, 9999)
-			if field.Kind() == reflect.Ptr {
-				field.Set(reflect.ValueOf(&val))
-			} else {
-				field.SetInt(int64(val))
+			val := reflect.New(v.Type().Elem()).Elem()
+			valOK, err := g.fill(val, elemTag)
+			if err != nil {
+				return false, err
 			}
-		case "time":
-			val := RandTime(time.Now(), time.Now().Add(30*24*time.Hour))
-			if field.Kind() == reflect.Ptr {
-				field.Set(reflect.ValueOf(&val))
-			} else {
-				field.Set(reflect.ValueOf(val))
+			if keyOK && valOK {
+				m.SetMapIndex(key, val)
 			}
 		}
+		v.Set(m)
+		return true, nil
+
+	case reflect.Struct:
+		if tag.kind != "" || v.Type() == timeType {
+			return true, g.setScalar(v, tag)
+		}
+
+		t := v.Type()
+		if g.depth[t] > g.f.cycleLimit {
+			return false, nil
+		}
+		g.depth[t]++
+		defer func() { g.depth[t]-- }()
+
+		return true, g.fillStruct(v)
+
+	default:
+		return true, g.setScalar(v, tag)
 	}
+}
 
-	return t
+// fillElem fills element i of a slice or array, adding its index to the path.
+// Only the first element's rules can be broken by GenerateInvalidFakes.
+func (g *generator) fillElem(v reflect.Value, i int, tag fakeTag) (bool, error) {
+	last := len(g.path) - 1
+	name := g.path[last]
+	g.path[last] = name + "[" + strconv.Itoa(i) + "]"
+	if i > 0 {
+		g.skip++
+	}
+
+	ok, err := g.fill(v, tag)
+
+	g.path[last] = name
+	if i > 0 {
+		g.skip--
+	}
+	return ok, err
+}
+
+// length returns a random length in the tag's len range, or def when it has none.
+func (g *generator) length(tag fakeTag, def int) int {
+	if tag.minLen < 0 {
+		return def
+	}
+	if tag.minLen == tag.maxLen {
+		return tag.minLen
+	}
+	return tag.minLen + g.f.intN(tag.maxLen-tag.minLen+1)
+}
+
+// setScalar sets v to a random value of tag's kind.
+func (g *generator) setScalar(v reflect.Value, tag fakeTag) error {
+	f := g.f
+
+	if gen, ok := tagGenerator(tag.kind); ok {
+		return setGenerated(v, gen(f, g.field), tag.kind)
+	}
+
+	switch {
+	case tag.kind == "string" && v.Kind() == reflect.String:
+		v.SetString(f.RandString(g.length(tag, 20)))
+	case tag.kind == "sentence" && v.Kind() == reflect.String:
+		if words := g.length(tag, 2); words > 0 {
+			v.SetString(f.RandSentence(words))
+		}
+	case tag.kind == "uuid_str" && v.Kind() == reflect.String:
+		v.SetString(f.UUID())
+	case tag.kind == "email" && v.Kind() == reflect.String:
+		v.SetString(f.RandEmail())
+	case tag.kind == "url" && v.Kind() == reflect.String:
+		v.SetString(f.RandURL())
+	case tag.kind == "bool" && v.Kind() == reflect.Bool:
+		v.SetBool(f.RandBool())
+	case tag.kind == "int" && (v.CanInt() || v.CanUint() || v.CanFloat()):
+		return g.setInt(v, tag)
+	case tag.kind == "float" && v.CanFloat():
+		lo, hi := bounds(tag, 99, 9999)
+		n := lo + f.float64()*(hi-lo)
+		v.SetFloat(math.Round(n*100) / 100)
+	case tag.kind == "time" && v.Kind() == reflect.Struct && v.Type().ConvertibleTo(timeType):
+		v.Set(reflect.ValueOf(g.randTime(tag)).Convert(v.Type()))
+	case tag.kind == "oneof":
+		return setOneOf(v, tag.oneof[f.intN(len(tag.oneof))])
+	case tag.kind == "":
+		return fmt.Errorf("a %s field needs a kind", v.Type())
+	default:
+		return fmt.Errorf("kind %s cannot fill a %s field", tag.kind, v.Type())
+	}
+
+	return nil
+}
+
+// setInt sets an integer or float field to a whole number in the tag range.
+func (g *generator) setInt(v reflect.Value, tag fakeTag) error {
+	defLo, defHi := intRange(v.Kind())
+	lo, hi := bounds(tag, float64(defLo), float64(defHi))
+
+	if lo != math.Trunc(lo) || hi != math.Trunc(hi) {
+		return errors.New("min and max must be whole numbers for kind int")
+	}
+
+	if v.CanUint() {
+		if lo < 0 || hi > math.MaxUint64 || v.OverflowUint(floatToUint64(hi)) {
+			return fmt.Errorf("range [%v, %v] does not fit a %s field", lo, hi, v.Type())
+		}
+
+		// The span is taken in uint64, so the full range does not overflow.
+		loU := floatToUint64(lo)
+		v.SetUint(loU + g.f.uint64N(floatToUint64(hi)-loU))
+		return nil
+	}
+
+	if lo < math.MinInt64 || hi > math.MaxInt64 ||
+		v.CanInt() && (v.OverflowInt(floatToInt64(lo)) || v.OverflowInt(floatToInt64(hi))) {
+		return fmt.Errorf("range [%v, %v] does not fit a %s field", lo, hi, v.Type())
+	}
+
+	// Two's complement makes hi-lo exact in uint64 even when it overflows int64.
+	loI := floatToInt64(lo)
+	n := loI + int64(g.f.uint64N(uint64(floatToInt64(hi))-uint64(loI)))
+
+	if v.CanInt() {
+		v.SetInt(n)
+	} else {
+		v.SetFloat(float64(n))
+	}
+
+	return nil
+}
+
+// floatToInt64 converts a whole number in the int64 range. float64 rounds
+// math.MaxInt64 up to 2^63, which is clamped back instead of overflowing.
+func floatToInt64(x float64) int64 {
+	if x >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(x)
+}
+
+// floatToUint64 converts a whole number in the uint64 range, clamping 2^64,
+// which is what float64 rounds math.MaxUint64 up to.
+func floatToUint64(x float64) uint64 {
+	if x >= math.MaxUint64 {
+		return math.MaxUint64
+	}
+	return uint64(x)
+}
+
+// randTime returns a time in [now-past, now+future].
+func (g *generator) randTime(tag fakeTag) time.Time {
+	future := time.Duration(0)
+	switch {
+	case tag.future != nil:
+		future = *tag.future
+	case tag.past == 0:
+		future = 30 * 24 * time.Hour
+	}
+
+	now := time.Now()
+	start, end := now.Add(-tag.past), now.Add(future)
+	if end.Unix() <= start.Unix() {
+		return start
+	}
+
+	return g.f.RandTime(start, end)
+}
+
+// bounds returns the min and max of tag, or the defaults. When only one bound
+// is set, the other default moves so they do not cross.
+func bounds(tag fakeTag, defLo, defHi float64) (float64, float64) {
+	lo, hi := defLo, defHi
+	if tag.min != nil {
+		lo = *tag.min
+		hi = max(hi, lo)
+	}
+	if tag.max != nil {
+		hi = *tag.max
+		if tag.min == nil {
+			lo = min(lo, hi)
+		}
+	}
+	return lo, hi
+}
+
+// setOneOf sets v to s converted to the field type.
+func setOneOf(v reflect.Value, s string) error {
+	invalid := fmt.Errorf("oneof value %q is not a valid %s", s, v.Type())
+
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(s)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return invalid
+		}
+		v.SetBool(b)
+	case v.CanInt():
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+		v.SetInt(n)
+	case v.CanUint():
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+		v.SetUint(n)
+	case v.CanFloat():
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return invalid
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("kind oneof cannot fill a %s field", v.Type())
+	}
+
+	return nil
+}
+
+// intRange returns the default range of the int kind, narrowed for 8-bit types.
+func intRange(kind reflect.Kind) (int, int) {
+	switch kind {
+	case reflect.Int8:
+		return 99, math.MaxInt8
+	case reflect.Uint8:
+		return 99, math.MaxUint8
+	default:
+		return 99, 9999
+	}
+}
+
+// defaultKind returns the kind used for map keys of type t, or "" for
+// registered types, see RegisterType.
+func defaultKind(t reflect.Type) string {
+	if _, ok := typeGenerator(t); ok {
+		return ""
+	}
+
+	switch {
+	case t.Kind() == reflect.String:
+		return "string"
+	case t.Kind() == reflect.Bool:
+		return "bool"
+	case t == timeType:
+		return "time"
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Float64:
+		return "int"
+	default:
+		return ""
+	}
+}
+
+// fillsUntagged reports whether t is a struct or registered type, or a pointer,
+// slice, array or map of them, which GenerateFake fills without a tag.
+func fillsUntagged(t reflect.Type) bool {
+	if _, ok := typeGenerator(t); ok {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return t != timeType
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return fillsUntagged(t.Elem())
+	default:
+		return false
+	}
 }
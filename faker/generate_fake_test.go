@@ -1,12 +1,15 @@
 package faker_test
 
 import (
+	"math"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shoraid/stx-go-utils/faker"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type TestStruct struct {
@@ -23,6 +26,50 @@ type MinimalStruct struct {
 	Flag *bool `faker:"bool"`
 }
 
+type Numbers struct {
+	I8   int8    `faker:"int"`
+	I64  int64   `faker:"int"`
+	U8   uint8   `faker:"int"`
+	U32  *uint32 `faker:"int"`
+	F32  float32 `faker:"float"`
+	F64  float64 `faker:"int"`
+	Skip int     `faker:"-"`
+}
+
+type Address struct {
+	Street string `faker:"sentence"`
+	City   string `faker:"string"`
+}
+
+type Timestamps struct {
+	CreatedAt time.Time `faker:"time"`
+}
+
+type audit struct {
+	UpdatedBy string `faker:"uuid_str"`
+}
+
+type Customer struct {
+	Timestamps
+	audit
+	Name      string `faker:"string"`
+	Home      Address
+	Work      *Address
+	Previous  []Address          `faker:"len=2"`
+	Tags      []string           `faker:"sentence,len=4"`
+	Scores    map[string]int     `faker:"int,len=2"`
+	Contacts  map[int]*Address   `faker:"len=1"`
+	Codes     [2]string          `faker:"string"`
+	Emails    []string           // no tag, stays nil
+	Untouched map[string]Address `faker:"-"`
+}
+
+type Category struct {
+	Name     string `faker:"string"`
+	Parent   *Category
+	Children []Category `faker:"len=2"`
+}
+
 func TestFaker_GenerateFake(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -59,6 +106,93 @@ func TestFaker_GenerateFake(t *testing.T) {
 				assert.NotNil(t, ms.Flag, "Flag should not be nil")
 			},
 		},
+		{
+			name: "every numeric kind",
+			generate: func() any {
+				return faker.GenerateFake[Numbers]()
+			},
+			assertions: func(t *testing.T, result any) {
+				n := result.(*Numbers)
+				assert.GreaterOrEqual(t, n.I8, int8(99))
+				assert.GreaterOrEqual(t, n.I64, int64(99))
+				assert.GreaterOrEqual(t, n.U8, uint8(99))
+				assert.NotNil(t, n.U32)
+				assert.GreaterOrEqual(t, *n.U32, uint32(99))
+				assert.GreaterOrEqual(t, n.F32, float32(99))
+				assert.GreaterOrEqual(t, n.F64, float64(99))
+				assert.Zero(t, n.Skip, "Skip is tagged -")
+			},
+		},
+		{
+			name: "nested, embedded and container fields",
+			generate: func() any {
+				return faker.GenerateFake[Customer]()
+			},
+			assertions: func(t *testing.T, result any) {
+				c := result.(*Customer)
+
+				assert.False(t, c.CreatedAt.IsZero(), "embedded struct should be filled")
+				assert.NotEmpty(t, c.UpdatedBy, "unexported embedded struct should be filled")
+				assert.NotEmpty(t, c.Home.Street)
+				assert.NotEmpty(t, c.Home.City)
+				require.NotNil(t, c.Work)
+				assert.NotEmpty(t, c.Work.City)
+
+				require.Len(t, c.Previous, 2)
+				for _, a := range c.Previous {
+					assert.NotEmpty(t, a.City)
+				}
+
+				require.Len(t, c.Tags, 4)
+				for _, tag := range c.Tags {
+					assert.NotEmpty(t, tag)
+				}
+
+				assert.Len(t, c.Scores, 2)
+				for k, v := range c.Scores {
+					assert.NotEmpty(t, k)
+					assert.GreaterOrEqual(t, v, 99)
+				}
+
+				require.Len(t, c.Contacts, 1)
+				for _, a := range c.Contacts {
+					require.NotNil(t, a)
+					assert.NotEmpty(t, a.City)
+				}
+
+				assert.NotEmpty(t, c.Codes[0])
+				assert.NotEmpty(t, c.Codes[1])
+				assert.Nil(t, c.Emails)
+				assert.Nil(t, c.Untouched)
+			},
+		},
+		{
+			name: "cycles stop at the default limit",
+			generate: func() any {
+				return faker.GenerateFake[Category]()
+			},
+			assertions: func(t *testing.T, result any) {
+				c := result.(*Category)
+
+				require.NotNil(t, c.Parent)
+				assert.NotEmpty(t, c.Parent.Name)
+				assert.Nil(t, c.Parent.Parent)
+				assert.NotNil(t, c.Parent.Children, "the slice is kept, its elements are skipped")
+				assert.Empty(t, c.Parent.Children)
+
+				require.Len(t, c.Children, 2)
+				assert.Nil(t, c.Children[0].Parent)
+			},
+		},
+		{
+			name: "non-struct type stays zero",
+			generate: func() any {
+				return faker.GenerateFake[int]()
+			},
+			assertions: func(t *testing.T, result any) {
+				assert.Equal(t, 0, *result.(*int))
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -69,8 +203,241 @@ func TestFaker_GenerateFake(t *testing.T) {
 	}
 }
 
+type Account struct {
+	Status    string    `faker:"oneof=active|inactive"`
+	Level     uint8     `faker:"oneof=1|2|3"`
+	Verified  bool      `faker:"oneof=true"`
+	Quantity  int       `faker:"int,min=1,max=10"`
+	Negative  int32     `faker:"int,min=-5,max=-1"`
+	OnlyMin   int       `faker:"int,min=20000"`
+	Price     float64   `faker:"float,min=0.5,max=2"`
+	Code      string    `faker:"string,len=8"`
+	Empty     string    `faker:"string,len=0"`
+	Headline  string    `faker:"sentence,len=5"`
+	LastLogin time.Time `faker:"time,past=30d"`
+	ExpiresAt time.Time `faker:"time,future=12h"`
+	Window    time.Time `faker:"time,past=1d,future=1d"`
+	Roles     []string  `faker:"oneof=admin|editor,len=2"`
+	Tokens    []string  `faker:"string,len=4"`
+}
+
+func TestFaker_GenerateFake_TagOptions(t *testing.T) {
+	for range 50 {
+		a := faker.GenerateFake[Account]()
+		now := time.Now()
+
+		assert.Contains(t, []string{"active", "inactive"}, a.Status)
+		assert.Contains(t, []uint8{1, 2, 3}, a.Level)
+		assert.True(t, a.Verified)
+		assert.GreaterOrEqual(t, a.Quantity, 1)
+		assert.LessOrEqual(t, a.Quantity, 10)
+		assert.GreaterOrEqual(t, a.Negative, int32(-5))
+		assert.LessOrEqual(t, a.Negative, int32(-1))
+		assert.GreaterOrEqual(t, a.OnlyMin, 20000)
+		assert.GreaterOrEqual(t, a.Price, 0.5)
+		assert.LessOrEqual(t, a.Price, 2.0)
+		assert.Len(t, a.Code, 8)
+		assert.Empty(t, a.Empty)
+		assert.Len(t, strings.Fields(a.Headline), 5)
+		assert.False(t, a.LastLogin.After(now), "past only")
+		assert.WithinDuration(t, now, a.LastLogin, 30*24*time.Hour+time.Second)
+		assert.False(t, a.ExpiresAt.Before(now.Add(-time.Second)), "future only")
+		assert.WithinDuration(t, now, a.ExpiresAt, 12*time.Hour+time.Second)
+		assert.WithinDuration(t, now, a.Window, 24*time.Hour+time.Second)
+
+		require.Len(t, a.Roles, 2)
+		for _, role := range a.Roles {
+			assert.Contains(t, []string{"admin", "editor"}, role)
+		}
+
+		// len sets the slice length; the strings keep their default length.
+		require.Len(t, a.Tokens, 4)
+		assert.Len(t, a.Tokens[0], 20)
+	}
+}
+
+func TestFaker_GenerateFake_IntExtremes(t *testing.T) {
+	type extremes struct {
+		Full   int64  `faker:"int,min=-9223372036854775808,max=9223372036854775807"`
+		Top    int64  `faker:"int,min=9223372036854775807,max=9223372036854775807"`
+		Bottom int64  `faker:"int,min=-9223372036854775808,max=-9223372036854775808"`
+		Wide   uint64 `faker:"int,min=0,max=18446744073709551615"`
+	}
+
+	f := faker.New(5)
+	var negative, positive, high bool
+
+	for range 50 {
+		e, err := faker.TryGenerateFake[extremes](f)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(math.MaxInt64), e.Top)
+		assert.Equal(t, int64(math.MinInt64), e.Bottom)
+
+		negative = negative || e.Full < 0
+		positive = positive || e.Full > 0
+		high = high || e.Wide > math.MaxInt64
+	}
+
+	assert.True(t, negative && positive, "the whole int64 range is drawn")
+	assert.True(t, high, "the whole uint64 range is drawn")
+}
+
+func TestFaker_TryGenerateFake_Errors(t *testing.T) {
+	type unknownKind struct {
+		Name string `faker:"name"`
+	}
+	type unknownOption struct {
+		Name string `faker:"string,size=3"`
+	}
+	type badNumber struct {
+		Age int `faker:"int,min=x"`
+	}
+	type crossedRange struct {
+		Age int `faker:"int,min=10,max=1"`
+	}
+	type wrongOption struct {
+		Age int `faker:"int,past=1d"`
+	}
+	type badDuration struct {
+		At time.Time `faker:"time,past=soon"`
+	}
+	type kindMismatch struct {
+		Active string `faker:"bool"`
+	}
+	type overflow struct {
+		Small int8 `faker:"int,max=1000"`
+	}
+	type negativeUint struct {
+		Count uint `faker:"int,min=-1"`
+	}
+	type badOneOf struct {
+		Level int `faker:"oneof=low|high"`
+	}
+	type emptyOneOf struct {
+		Status string `faker:"oneof="`
+	}
+	type nested struct {
+		Inner struct {
+			Code string `faker:"string,len=-1"`
+		}
+	}
+
+	tests := []struct {
+		name        string
+		generate    func() error
+		expectedErr string
+	}{
+		{
+			name:        "unknown kind",
+			generate:    func() error { _, err := faker.TryGenerateFake[unknownKind](faker.New(1)); return err },
+			expectedErr: `faker: field unknownKind.Name: tag "name": unknown kind "name"`,
+		},
+		{
+			name:        "unknown option",
+			generate:    func() error { _, err := faker.TryGenerateFake[unknownOption](faker.New(1)); return err },
+			expectedErr: `faker: field unknownOption.Name: tag "string,size=3": unknown option "size"`,
+		},
+		{
+			name:        "bad number",
+			generate:    func() error { _, err := faker.TryGenerateFake[badNumber](faker.New(1)); return err },
+			expectedErr: `faker: field badNumber.Age: tag "int,min=x": min must be a number, got "x"`,
+		},
+		{
+			name:        "min greater than max",
+			generate:    func() error { _, err := faker.TryGenerateFake[crossedRange](faker.New(1)); return err },
+			expectedErr: `faker: field crossedRange.Age: tag "int,min=10,max=1": min 10 is greater than max 1`,
+		},
+		{
+			name:        "option of another kind",
+			generate:    func() error { _, err := faker.TryGenerateFake[wrongOption](faker.New(1)); return err },
+			expectedErr: `faker: field wrongOption.Age: tag "int,past=1d": option past is not valid for kind int`,
+		},
+		{
+			name:        "bad duration",
+			generate:    func() error { _, err := faker.TryGenerateFake[badDuration](faker.New(1)); return err },
+			expectedErr: `faker: field badDuration.At: tag "time,past=soon": past must be a duration like 12h or 30d, got "soon"`,
+		},
+		{
+			name:        "kind does not fit the field",
+			generate:    func() error { _, err := faker.TryGenerateFake[kindMismatch](faker.New(1)); return err },
+			expectedErr: `faker: field kindMismatch.Active: tag "bool": kind bool cannot fill a string field`,
+		},
+		{
+			name:        "range overflows the field",
+			generate:    func() error { _, err := faker.TryGenerateFake[overflow](faker.New(1)); return err },
+			expectedErr: `faker: field overflow.Small: tag "int,max=1000": range [99, 1000] does not fit a int8 field`,
+		},
+		{
+			name:        "negative range for unsigned field",
+			generate:    func() error { _, err := faker.TryGenerateFake[negativeUint](faker.New(1)); return err },
+			expectedErr: `faker: field negativeUint.Count: tag "int,min=-1": range [-1, 9999] does not fit a uint field`,
+		},
+		{
+			name:        "oneof value of the wrong type",
+			generate:    func() error { _, err := faker.TryGenerateFake[badOneOf](faker.New(1)); return err },
+			expectedErr: `is not a valid int`,
+		},
+		{
+			name:        "empty oneof",
+			generate:    func() error { _, err := faker.TryGenerateFake[emptyOneOf](faker.New(1)); return err },
+			expectedErr: `faker: field emptyOneOf.Status: tag "oneof=": oneof needs at least one value`,
+		},
+		{
+			name:        "nested field path",
+			generate:    func() error { _, err := faker.TryGenerateFake[nested](faker.New(1)); return err },
+			expectedErr: `faker: field nested.Inner.Code: tag "string,len=-1": len must be a non-negative integer, got "-1"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.generate()
+
+			var tagErr *faker.TagError
+			require.ErrorAs(t, err, &tagErr)
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+
+	assert.Panics(t, func() { faker.GenerateFake[unknownKind]() })
+}
+
+func TestFaker_GenerateFake_CycleLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int
+		expectedDepth int
+	}{
+		{name: "no nesting", limit: 0, expectedDepth: 0},
+		{name: "one level", limit: 1, expectedDepth: 1},
+		{name: "three levels", limit: 3, expectedDepth: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := faker.New(1)
+			f.SetCycleLimit(tt.limit)
+
+			c := faker.GenerateFakeWith[Category](f)
+
+			depth := 0
+			for p := c.Parent; p != nil; p = p.Parent {
+				depth++
+			}
+			assert.Equal(t, tt.expectedDepth, depth)
+		})
+	}
+}
+
 func BenchmarkGenerateFake(b *testing.B) {
 	for b.Loop() {
 		faker.GenerateFake[TestStruct]()
 	}
 }
+
+func BenchmarkGenerateFake_Nested(b *testing.B) {
+	for b.Loop() {
+		faker.GenerateFake[Customer]()
+	}
+}
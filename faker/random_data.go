@@ -2,7 +2,6 @@ package faker
 
 import (
 	"fmt"
-	"math/rand"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,142 +9,246 @@ import (
 	"github.com/shoraid/stx-go-utils/genericutil"
 )
 
+var (
+	emailUsernames = []string{
+		"john", "jane", "alex", "mike", "sara",
+		"emma", "lisa", "david", "kevin", "nina",
+		"peter", "sophia", "mark", "olivia", "jack",
+		"lucas", "mia", "ryan", "chloe", "daniel",
+		"zoe", "adam", "ella", "sam", "grace",
+		"noah", "ava", "liam", "isabella", "ethan",
+	}
+
+	emailDomains = []string{
+		"gmail.com", "yahoo.com", "outlook.com", "hotmail.com", "icloud.com",
+		"example.com", "test.com", "dummy.net", "sample.org", "mail.com",
+	}
+
+	sentenceWords = []string{
+		"lorem", "ipsum", "dolor", "sit", "amet",
+		"consectetur", "adipiscing", "elit",
+		"sed", "do", "eiusmod", "tempor", "incididunt",
+		"ut", "labore", "et", "dolore", "magna", "aliqua",
+		"Ut", "enim", "ad", "minim", "veniam",
+		"quis", "nostrud", "exercitation", "ullamco", "laboris",
+		"nisi", "aliquip", "ex", "ea", "commodo", "consequat",
+		"Duis", "aute", "irure", "in", "reprehenderit",
+		"voluptate", "velit", "esse", "cillum", "eu",
+		"fugiat", "nulla", "pariatur",
+		"Excepteur", "sint", "occaecat", "cupidatat", "non", "proident",
+		"sunt", "culpa", "qui", "officia", "deserunt",
+		"mollit", "anim", "id", "est", "laborum",
+	}
+
+	urlDomains = []string{"example.com", "test.com", "dummy.net", "sample.org"}
+)
+
 // PickRandom returns a random element from the provided list.
 func PickRandom(elements ...any) any {
-	index := RandInt(0, len(elements)-1)
+	return Default().PickRandom(elements...)
+}
+
+// PickRandom returns a random element from the provided list.
+func (f *Faker) PickRandom(elements ...any) any {
+	index := f.RandInt(0, len(elements)-1)
 	return elements[index]
 }
 
 // RandBool returns a random boolean value (true or false).
 func RandBool() bool {
-	return rand.Intn(2) == 1
+	return Default().RandBool()
+}
+
+// RandBool returns a random boolean value (true or false).
+func (f *Faker) RandBool() bool {
+	return f.intN(2) == 1
 }
 
 // RandBoolPtr returns a pointer to a random boolean value (true or false).
 func RandBoolPtr() *bool {
-	return genericutil.Ptr(RandBool())
+	return Default().RandBoolPtr()
+}
+
+// RandBoolPtr returns a pointer to a random boolean value (true or false).
+func (f *Faker) RandBoolPtr() *bool {
+	return genericutil.Ptr(f.RandBool())
 }
 
 // RandEmail generates a random email address in the format "username123@domain".
 func RandEmail() string {
-	usernames := []string{
-		"john", "jane", "alex", "mike", "sara",
-		"emma", "lisa", "david", "kevin", "nina",
-		"peter", "sophia", "mark", "olivia", "jack",
-		"lucas", "mia", "ryan", "chloe", "daniel",
-		"zoe", "adam", "ella", "sam", "grace",
-		"noah", "ava", "liam", "isabella", "ethan",
-	}
-
-	domains := []string{
-		"gmail.com", "yahoo.com", "outlook.com", "hotmail.com", "icloud.com",
-		"example.com", "test.com", "dummy.net", "sample.org", "mail.com",
-	}
+	return Default().RandEmail()
+}
 
-	username := usernames[rand.Intn(len(usernames))]
+// RandEmail generates a random email address in the format "username123@domain".
+func (f *Faker) RandEmail() string {
+	username := emailUsernames[f.intN(len(emailUsernames))]
 
-	usernameWithDigits := username + fmt.Sprintf("%03d", RandInt(0, 999))
+	usernameWithDigits := username + fmt.Sprintf("%03d", f.RandInt(0, 999))
 
-	domain := domains[rand.Intn(len(domains))]
+	domain := emailDomains[f.intN(len(emailDomains))]
 
 	return usernameWithDigits + "@" + domain
 }
 
 // RandEmailPtr returns a pointer to a randomly generated email address.
 func RandEmailPtr() *string {
-	return genericutil.Ptr(RandEmail())
+	return Default().RandEmailPtr()
+}
+
+// RandEmailPtr returns a pointer to a randomly generated email address.
+func (f *Faker) RandEmailPtr() *string {
+	return genericutil.Ptr(f.RandEmail())
 }
 
 // RandInt returns a random integer within the range [min, max].
 func RandInt(min, max int) int {
-	return rand.Intn(max-min+1) + min
+	return Default().RandInt(min, max)
+}
+
+// RandInt returns a random integer within the range [min, max].
+func (f *Faker) RandInt(min, max int) int {
+	return f.intN(max-min+1) + min
 }
 
 // RandIntPtr returns a pointer to a random integer within the range [min, max].
 func RandIntPtr(min, max int) *int {
-	return genericutil.Ptr(RandInt(min, max))
+	return Default().RandIntPtr(min, max)
+}
+
+// RandIntPtr returns a pointer to a random integer within the range [min, max].
+func (f *Faker) RandIntPtr(min, max int) *int {
+	return genericutil.Ptr(f.RandInt(min, max))
 }
 
 // RandSentence generates a random sentence consisting of `wordCount` words.
 func RandSentence(wordCount int) string {
-	words := []string{
-		"lorem", "ipsum", "dolor", "sit", "amet",
-		"consectetur", "adipiscing", "elit",
-		"sed", "do", "eiusmod", "tempor", "incididunt",
-		"ut", "labore", "et", "dolore", "magna", "aliqua",
-		"Ut", "enim", "ad", "minim", "veniam",
-		"quis", "nostrud", "exercitation", "ullamco", "laboris",
-		"nisi", "aliquip", "ex", "ea", "commodo", "consequat",
-		"Duis", "aute", "irure", "in", "reprehenderit",
-		"voluptate", "velit", "esse", "cillum", "eu",
-		"fugiat", "nulla", "pariatur",
-		"Excepteur", "sint", "occaecat", "cupidatat", "non", "proident",
-		"sunt", "culpa", "qui", "officia", "deserunt",
-		"mollit", "anim", "id", "est", "laborum",
-	}
+	return Default().RandSentence(wordCount)
+}
+
+// RandSentence generates a random sentence consisting of `wordCount` words.
+func (f *Faker) RandSentence(wordCount int) string {
 	result := ""
 	for range wordCount {
-		result += words[rand.Intn(len(words))] + " "
+		result += sentenceWords[f.intN(len(sentenceWords))] + " "
 	}
 	return result[:len(result)-1]
 }
 
 // RandSentencePtr returns a pointer to a random sentence consisting of `wordCount` words.
 func RandSentencePtr(wordCount int) *string {
-	return genericutil.Ptr(RandSentence(wordCount))
+	return Default().RandSentencePtr(wordCount)
+}
+
+// RandSentencePtr returns a pointer to a random sentence consisting of `wordCount` words.
+func (f *Faker) RandSentencePtr(wordCount int) *string {
+	return genericutil.Ptr(f.RandSentence(wordCount))
 }
 
 // RandString generates a random alphanumeric string with a given length.
 func RandString(length int) string {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	return Default().RandString(length)
+}
 
-	seed := rand.NewSource(time.Now().UnixNano())
-	rng := rand.New(seed)
+// RandString generates a random alphanumeric string with a given length.
+func (f *Faker) RandString(length int) string {
+	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 
 	result := make([]byte, length)
 	for i := range result {
-		result[i] = charset[rng.Intn(len(charset))]
+		result[i] = charset[f.intN(len(charset))]
 	}
 	return string(result)
 }
 
 // RandStringPtr returns a pointer to a random alphanumeric string with a given length.
 func RandStringPtr(length int) *string {
-	return genericutil.Ptr(RandString(length))
+	return Default().RandStringPtr(length)
+}
+
+// RandStringPtr returns a pointer to a random alphanumeric string with a given length.
+func (f *Faker) RandStringPtr(length int) *string {
+	return genericutil.Ptr(f.RandString(length))
 }
 
 // RandTime generates a random time between `start` and `end`.
 func RandTime(start, end time.Time) time.Time {
+	return Default().RandTime(start, end)
+}
+
+// RandTime generates a random time between `start` and `end`.
+func (f *Faker) RandTime(start, end time.Time) time.Time {
 	if start.After(end) {
 		start, end = end, start
 	}
-	duration := rand.Int63n(end.Unix() - start.Unix())
+	duration := f.int64N(end.Unix() - start.Unix())
 	return time.Unix(start.Unix()+duration, 0)
 }
 
 // RandTimePtr returns a pointer to a random time between `start` and `end`.
 func RandTimePtr(start, end time.Time) *time.Time {
-	t := RandTime(start, end)
+	return Default().RandTimePtr(start, end)
+}
+
+// RandTimePtr returns a pointer to a random time between `start` and `end`.
+func (f *Faker) RandTimePtr(start, end time.Time) *time.Time {
+	t := f.RandTime(start, end)
 	return &t
 }
 
 // RandURL generates a random URL with a random domain.
 func RandURL() string {
-	domains := []string{"example.com", "test.com", "dummy.net", "sample.org"}
-	return "https://" + RandString(8) + "." + domains[rand.Intn(len(domains))]
+	return Default().RandURL()
+}
+
+// RandURL generates a random URL with a random domain.
+func (f *Faker) RandURL() string {
+	return "https://" + f.RandString(8) + "." + urlDomains[f.intN(len(urlDomains))]
 }
 
 // RandURLPtr returns a pointer to a random URL with a random domain.
 func RandURLPtr() *string {
-	return genericutil.Ptr(RandURL())
+	return Default().RandURLPtr()
+}
+
+// RandURLPtr returns a pointer to a random URL with a random domain.
+func (f *Faker) RandURLPtr() *string {
+	return genericutil.Ptr(f.RandURL())
 }
 
 // UUID generates a random UUID v7.
 func UUID() string {
-	return uuid.Must(uuid.NewV7()).String()
+	return Default().UUID()
+}
+
+// uuidTimeStart and uuidTimeSpan bound the timestamps of generated UUIDs, in
+// Unix milliseconds: 2020-01-01 up to 2030-01-01.
+const (
+	uuidTimeStart = 1_577_836_800_000
+	uuidTimeSpan  = 1_893_456_000_000 - uuidTimeStart
+)
+
+// UUID generates a random UUID v7. Its timestamp, between 2020 and 2030, and
+// its random bits both come from the Faker, so a seed reproduces it.
+func (f *Faker) UUID() string {
+	var u uuid.UUID
+	f.Read(u[:])
+
+	ms := uint64(uuidTimeStart + f.int64N(uuidTimeSpan))
+	for i := range 6 {
+		u[i] = byte(ms >> (40 - 8*i))
+	}
+	u[6] = u[6]&0x0f | 0x70 // version 7
+	u[8] = u[8]&0x3f | 0x80 // RFC 9562 variant
+
+	return u.String()
 }
 
 // UUIDPtr returns a pointer to a random UUID v7.
 func UUIDPtr() *string {
-	return genericutil.Ptr(uuid.Must(uuid.NewV7()).String())
+	return Default().UUIDPtr()
+}
+
+// UUIDPtr returns a pointer to a random UUID v7, see Faker.UUID.
+func (f *Faker) UUIDPtr() *string {
+	return genericutil.Ptr(f.UUID())
 }
@@ -336,6 +336,10 @@ func TestFaker_UUID(t *testing.T) {
 				parsed, err := uuid.Parse(id)
 				assert.NoError(t, err, "should be valid UUID")
 				assert.Equal(t, uuid.Version(7), parsed.Version(), "should be UUIDv7")
+				assert.Equal(t, uuid.RFC4122, parsed.Variant())
+				sec, _ := parsed.Time().UnixTime()
+				year := time.Unix(sec, 0).UTC().Year()
+				assert.True(t, year >= 2020 && year < 2030, "timestamp year %d", year)
 
 				_, exists := seen[id]
 				assert.False(t, exists, "UUID must be unique")
@@ -0,0 +1,119 @@
+package faker
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	registryMu     sync.RWMutex
+	tagGenerators  = map[string]func(f *Faker, field reflect.StructField) any{}
+	typeGenerators = map[reflect.Type]func(f *Faker) reflect.Value{}
+)
+
+// RegisterTag registers a kind for faker tags, e.g. `faker:"phone"`, generated
+// by gen. gen gets the Faker to draw from and the struct field being filled,
+// and returns a value of the field type, or of a type convertible to it, such
+// as a string for a `type Phone string` field.
+//
+// Pointer, slice, array and map fields get a value from gen for each element,
+// with len setting their length as for built-in kinds. Registering a name
+// again replaces its generator. RegisterTag panics if name is empty, is a
+// built-in kind or contains any of ",=|".
+//
+// Example:
+//
+//	faker.RegisterTag("phone", func(f *faker.Faker, field reflect.StructField) any {
+//	    return fmt.Sprintf("+62812%07d", f.RandInt(0, 9_999_999))
+//	})
+//
+//	type Contact struct {
+//	    Phone  string   `faker:"phone"`
+//	    Others []string `faker:"phone,len=2"`
+//	}
+func RegisterTag(name string, gen func(f *Faker, field reflect.StructField) any) {
+	if _, ok := kindOptions[name]; ok || name == "" || name == "oneof" || strings.ContainsAny(name, ",=|") {
+		panic(fmt.Sprintf("faker: cannot register tag %q", name))
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	tagGenerators[name] = gen
+}
+
+// RegisterType registers gen as the generator of every T filled without a
+// kind: untagged fields and fields tagged only with options like len, and the
+// T behind pointers and in slices, arrays and map values. Registered types are
+// filled even without a tag, and win over their validate tag when the Faker
+// reads those, see SetValidateTags. A field tagged with a kind keeps using it.
+//
+// Registering T again replaces its generator.
+//
+// Example:
+//
+//	type Money struct {
+//	    Amount   int64
+//	    Currency string
+//	}
+//
+//	faker.RegisterType(func(f *faker.Faker) Money {
+//	    return Money{Amount: int64(f.RandInt(100, 100_000)), Currency: "IDR"}
+//	})
+//
+//	type Invoice struct {
+//	    Total    Money
+//	    Discount *Money
+//	    Lines    []Money `faker:"len=5"`
+//	}
+func RegisterType[T any](gen func(f *Faker) T) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	typeGenerators[reflect.TypeFor[T]()] = func(f *Faker) reflect.Value {
+		v := gen(f)
+		return reflect.ValueOf(&v).Elem()
+	}
+}
+
+func tagGenerator(name string) (func(f *Faker, field reflect.StructField) any, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	gen, ok := tagGenerators[name]
+	return gen, ok
+}
+
+func typeGenerator(t reflect.Type) (func(f *Faker) reflect.Value, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	gen, ok := typeGenerators[t]
+	return gen, ok
+}
+
+// setGenerated sets v to val returned by the generator of tag, converting it
+// between types of the same kind or between numeric types.
+func setGenerated(v reflect.Value, val any, tag string) error {
+	if val == nil {
+		return nil
+	}
+
+	rv := reflect.ValueOf(val)
+	switch {
+	case rv.Type().AssignableTo(v.Type()):
+		v.Set(rv)
+	case rv.Type().ConvertibleTo(v.Type()) && (rv.Kind() == v.Kind() || isNumber(rv.Kind()) && isNumber(v.Kind())):
+		v.Set(rv.Convert(v.Type()))
+	default:
+		return fmt.Errorf("kind %s returned a %s, which cannot fill a %s field", tag, rv.Type(), v.Type())
+	}
+
+	return nil
+}
+
+func isNumber(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
@@ -0,0 +1,185 @@
+package faker_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shoraid/stx-go-utils/faker"
+	"github.com/shoraid/stx-go-utils/structutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Phone string
+
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+type TenantID int64
+
+type Contact struct {
+	Phone    string   `faker:"phone"`
+	Mobile   Phone    `faker:"phone"`
+	Backup   *string  `faker:"phone"`
+	Others   []string `faker:"phone,len=2"`
+	Internal string   `faker:"field_name"`
+}
+
+type Invoice struct {
+	Tenant   TenantID
+	Total    Money
+	Discount *Money
+	Lines    []Money `faker:"len=5"`
+	Taxes    map[string]Money
+	Fixed    TenantID `faker:"int,min=7,max=7"`
+	Note     string
+}
+
+func init() {
+	faker.RegisterTag("phone", func(f *faker.Faker, field reflect.StructField) any {
+		return fmt.Sprintf("+62812%07d", f.RandInt(0, 9_999_999))
+	})
+	faker.RegisterTag("field_name", func(f *faker.Faker, field reflect.StructField) any {
+		return strings.ToLower(field.Name)
+	})
+	faker.RegisterTag("wrong_type", func(f *faker.Faker, field reflect.StructField) any {
+		return 42
+	})
+
+	faker.RegisterType(func(f *faker.Faker) Money {
+		return Money{Amount: int64(f.RandInt(100, 100_000)), Currency: "IDR"}
+	})
+	faker.RegisterType(func(f *faker.Faker) Phone {
+		return Phone(fmt.Sprintf("+62813%07d", f.RandInt(0, 9_999_999)))
+	})
+	faker.RegisterType(func(f *faker.Faker) TenantID {
+		return TenantID(f.RandInt(1, 5))
+	})
+}
+
+func TestFaker_RegisterTag(t *testing.T) {
+	c := faker.GenerateFake[Contact]()
+
+	assert.Regexp(t, `^\+62812\d{7}$`, c.Phone)
+	assert.Regexp(t, `^\+62812\d{7}$`, string(c.Mobile), "a kind in the tag wins over the registered type")
+	require.NotNil(t, c.Backup)
+	assert.Regexp(t, `^\+62812\d{7}$`, *c.Backup)
+	require.Len(t, c.Others, 2)
+	assert.Regexp(t, `^\+62812\d{7}$`, c.Others[1])
+	assert.Equal(t, "internal", c.Internal)
+}
+
+func TestFaker_RegisterTag_Errors(t *testing.T) {
+	type wrongType struct {
+		Name string `faker:"wrong_type"`
+	}
+	type optionOnCustom struct {
+		Phone string `faker:"phone,min=1"`
+	}
+
+	tests := []struct {
+		name        string
+		generate    func() error
+		expectedErr string
+	}{
+		{
+			name:        "value does not fit the field",
+			generate:    func() error { _, err := faker.TryGenerateFake[wrongType](faker.New(1)); return err },
+			expectedErr: `faker: field wrongType.Name: tag "wrong_type": kind wrong_type returned a int, which cannot fill a string field`,
+		},
+		{
+			name:        "option of another kind",
+			generate:    func() error { _, err := faker.TryGenerateFake[optionOnCustom](faker.New(1)); return err },
+			expectedErr: `faker: field optionOnCustom.Phone: tag "phone,min=1": option min is not valid for kind phone`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.generate()
+
+			var tagErr *faker.TagError
+			require.ErrorAs(t, err, &tagErr)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestFaker_RegisterTag_Panics(t *testing.T) {
+	gen := func(f *faker.Faker, field reflect.StructField) any { return "" }
+
+	for _, name := range []string{"", "string", "oneof", "a,b", "a=b", "a|b"} {
+		assert.Panics(t, func() { faker.RegisterTag(name, gen) }, "name %q", name)
+	}
+}
+
+func TestFaker_RegisterType(t *testing.T) {
+	inv := faker.GenerateFake[Invoice]()
+
+	assert.GreaterOrEqual(t, inv.Tenant, TenantID(1))
+	assert.LessOrEqual(t, inv.Tenant, TenantID(5))
+	assert.Equal(t, "IDR", inv.Total.Currency)
+	assert.GreaterOrEqual(t, inv.Total.Amount, int64(100))
+
+	require.NotNil(t, inv.Discount)
+	assert.Equal(t, "IDR", inv.Discount.Currency)
+
+	require.Len(t, inv.Lines, 5)
+	for _, line := range inv.Lines {
+		assert.Equal(t, "IDR", line.Currency)
+	}
+
+	require.NotEmpty(t, inv.Taxes)
+	for _, tax := range inv.Taxes {
+		assert.Equal(t, "IDR", tax.Currency)
+	}
+
+	assert.Equal(t, TenantID(7), inv.Fixed, "a kind in the tag wins over the registered type")
+	assert.Empty(t, inv.Note)
+}
+
+func TestFaker_RegisterType_ValidateTags(t *testing.T) {
+	type Order struct {
+		Tenant TenantID `json:"tenant" validate:"required"`
+		Total  Money    `json:"total" validate:"required"`
+		Phones []Phone  `json:"phones" validate:"required,dive,e164"`
+	}
+
+	f := faker.New(1)
+	f.SetValidateTags(true)
+
+	order, err := faker.TryGenerateFake[Order](f)
+	require.NoError(t, err)
+
+	assert.NotZero(t, order.Tenant)
+	assert.Equal(t, "IDR", order.Total.Currency)
+	require.NotEmpty(t, order.Phones)
+	assert.Regexp(t, `^\+62813\d{7}$`, string(order.Phones[0]))
+
+	_, err = structutil.Validate(order)
+	require.NoError(t, err)
+
+	fakes, err := faker.GenerateInvalidFakes[Order](faker.New(1))
+	require.NoError(t, err)
+
+	var broken []string
+	for _, fake := range fakes {
+		broken = append(broken, fake.Field+" "+fake.Rule)
+	}
+	assert.ElementsMatch(t, []string{"Order.Tenant required", "Order.Phones required"}, broken)
+
+	for _, fake := range fakes {
+		_, err := structutil.Validate(fake.Value)
+		assert.Error(t, err, fake.Field)
+	}
+}
+
+func BenchmarkGenerateFake_RegisterType(b *testing.B) {
+	for b.Loop() {
+		faker.GenerateFake[Invoice]()
+	}
+}
@@ -0,0 +1,534 @@
+package faker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// validateRules lists the validate rules each shape of field supports, see validateShape.
+var validateRules = map[string][]string{
+	"string":    {"required", "omitempty", "email", "uuid", "url", "oneof", "len", "min", "max", "gte", "lte"},
+	"bool":      {"required", "omitempty"},
+	"int":       {"required", "omitempty", "oneof", "len", "min", "max", "gte", "lte"},
+	"float":     {"required", "omitempty", "len", "min", "max", "gte", "lte"},
+	"container": {"required", "omitempty", "len", "min", "max", "gte", "lte"},
+	"time":      {"required", "omitempty"},
+	"struct":    {"required", "omitempty"},
+}
+
+// formatKinds maps the format rules to the kind generating them.
+var formatKinds = map[string]string{"email": "email", "uuid": "uuid_str", "url": "url"}
+
+// invalidFormats holds a value breaking each format rule.
+var invalidFormats = map[string]string{"email": "not-an-email", "uuid": "not-a-uuid", "url": "not a url"}
+
+// oneOfValue matches a value of a validate oneof rule, which may be single-quoted.
+var oneOfValue = regexp.MustCompile(`'[^']*'|\S+`)
+
+// SetValidateTags makes GenerateFakeWith fill fields without a faker tag from
+// their `validate` tag, so the result passes structutil.Validate. Fields with
+// a faker tag keep using it. It must be called before the Faker is used.
+//
+// The supported rules are:
+//
+//	required          a non-zero value, e.g. true for a bool
+//	omitempty         ignored
+//	email, uuid, url  a value of that format
+//	oneof             one of the space-separated values, for strings and integers
+//	len, min, max     the length of strings, slices and maps, the value of numbers
+//	gte, lte          same as min and max
+//	dive              the rules after it apply to the elements
+//
+// Other rules are reported as a *TagError; give those fields a faker tag.
+// Slices and maps get at least one element when their rules allow it. A
+// required pointer left nil by the cycle limit still fails validation.
+//
+// Example:
+//
+//	type SignupRequest struct {
+//	    Email string   `json:"email" validate:"required,email,max=100"`
+//	    Plan  string   `json:"plan" validate:"required,oneof=free pro"`
+//	    Age   int      `json:"age" validate:"min=18,max=120"`
+//	    Tags  []string `json:"tags" validate:"max=5,dive,min=2,max=10"`
+//	}
+//
+//	f := faker.New(42)
+//	f.SetValidateTags(true)
+//	req := faker.GenerateFakeWith[SignupRequest](f)
+//	_, err := structutil.Validate(req) // nil
+func (f *Faker) SetValidateTags(on bool) {
+	f.validateTags = on
+}
+
+// InvalidFake is a fake value breaking one validate rule, see GenerateInvalidFakes.
+type InvalidFake[T any] struct {
+	// Field is the path of the invalid field, e.g. "SignupRequest.Email" or
+	// "Order.Lines[0].Qty".
+	Field string
+	// Rule is the broken rule as written in the tag, e.g. "max=100".
+	Rule  string
+	Value *T
+}
+
+// GenerateInvalidFakes returns, for every validate rule of T, a fake T that
+// breaks that rule, for negative tests. Each fake is generated as with
+// SetValidateTags, then the one field is changed, e.g. to a value too long
+// for max or to the zero value for required.
+//
+// Rules after dive are broken on the first element only. Rules in map values,
+// and rules that cannot be broken, like required on a struct value or min=0,
+// are left out. A *TagError is returned if T cannot be generated.
+//
+// Example:
+//
+//	fakes, err := faker.GenerateInvalidFakes[SignupRequest](faker.ForTest(t))
+//	require.NoError(t, err)
+//
+//	for _, fake := range fakes {
+//	    t.Run(fake.Field+" "+fake.Rule, func(t *testing.T) {
+//	        _, err := structutil.Validate(fake.Value)
+//	        assert.ErrorIs(t, err, apperror.Err400InvalidData)
+//	    })
+//	}
+func GenerateInvalidFakes[T any](f *Faker) ([]InvalidFake[T], error) {
+	if reflect.TypeFor[T]().Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	finder := &breaker{}
+	if _, err := generateBroken[T](f, finder); err != nil {
+		return nil, err
+	}
+
+	fakes := make([]InvalidFake[T], 0, len(finder.found))
+	for _, target := range finder.found {
+		b := &breaker{target: &target}
+		t, err := generateBroken[T](f, b)
+		if err != nil {
+			return nil, err
+		}
+		if b.hit {
+			fakes = append(fakes, InvalidFake[T]{Field: target.field, Rule: target.rule, Value: t})
+		}
+	}
+
+	return fakes, nil
+}
+
+// generateBroken generates a T from its validate tags, letting b find or break rules.
+func generateBroken[T any](f *Faker, b *breaker) (*T, error) {
+	t := new(T)
+	v := reflect.ValueOf(t).Elem()
+
+	g := newGenerator(f, v.Type())
+	g.validate = true
+	g.breaker = b
+	if _, err := g.fill(v, newFakeTag("")); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+type validateRule struct {
+	name, param string
+	// raw is the rule as written, e.g. "max=100".
+	raw string
+}
+
+// parseValidateTag splits a validate tag at each dive, into the rules of the
+// value followed by the rules of its elements.
+func parseValidateTag(tag string) [][]validateRule {
+	levels := [][]validateRule{nil}
+
+	for _, raw := range strings.Split(tag, ",") {
+		raw = strings.TrimSpace(raw)
+		switch raw {
+		case "":
+		case "dive":
+			levels = append(levels, nil)
+		default:
+			name, param, _ := strings.Cut(raw, "=")
+			last := len(levels) - 1
+			levels[last] = append(levels[last], validateRule{name: name, param: param, raw: raw})
+		}
+	}
+
+	return levels
+}
+
+// breakableRules returns the rules GenerateInvalidFakes can break on a value not
+// generated from them, and whether they include omitempty.
+func breakableRules(rules []validateRule) ([]validateRule, bool) {
+	var breakable []validateRule
+	omitempty := false
+
+	for _, r := range rules {
+		switch r.name {
+		case "omitempty":
+			omitempty = true
+		case "required", "email", "uuid", "url", "oneof", "len", "min", "max", "gte", "lte":
+			breakable = append(breakable, r)
+		}
+	}
+
+	return breakable, omitempty
+}
+
+// validateFakeTag returns the fake tag generating a value of type t that passes
+// the rules of levels, as returned by parseValidateTag.
+func validateFakeTag(t reflect.Type, levels [][]validateRule) (fakeTag, error) {
+	var rules []validateRule
+	if len(levels) > 0 {
+		rules = levels[0]
+	}
+
+	base := t
+	for base.Kind() == reflect.Pointer {
+		base = base.Elem()
+	}
+
+	tag := newFakeTag(defaultKind(base))
+
+	// A registered type is generated as registered, whatever its rules.
+	if _, ok := typeGenerator(base); ok {
+		tag.rules, tag.omitempty = breakableRules(rules)
+		return tag, nil
+	}
+
+	shape := validateShape(base)
+	tag.rules = rules
+
+	var required bool
+	var format string
+	var lo, hi *float64
+
+	for _, r := range rules {
+		if !slices.Contains(validateRules[shape], r.name) {
+			return tag, fmt.Errorf("validate rule %s is not supported for a %s field", r.name, t)
+		}
+
+		switch r.name {
+		case "required":
+			required = true
+		case "omitempty":
+			tag.omitempty = true
+		case "email", "uuid", "url":
+			format = r.name
+		case "oneof":
+			if tag.oneof = oneOfValues(r.param); len(tag.oneof) == 0 {
+				return tag, errors.New("oneof needs at least one value")
+			}
+		default:
+			n, err := strconv.ParseFloat(r.param, 64)
+			if err != nil {
+				return tag, fmt.Errorf("%s must be a number, got %q", r.name, r.param)
+			}
+			if r.name != "max" && r.name != "lte" {
+				lo = &n
+			}
+			if r.name != "min" && r.name != "gte" {
+				hi = &n
+			}
+		}
+	}
+
+	if lo != nil && hi != nil && *lo > *hi {
+		return tag, fmt.Errorf("min %v is greater than max %v", *lo, *hi)
+	}
+
+	if len(levels) > 1 && shape != "container" {
+		return tag, fmt.Errorf("dive needs a slice, array or map field, not %s", t)
+	}
+
+	switch shape {
+	case "string":
+		switch {
+		case format != "":
+			tag.kind = formatKinds[format]
+		case tag.oneof != nil:
+			tag.kind = "oneof"
+		default:
+			tag.minLen, tag.maxLen = lenRange(lo, hi, 8, 20, required)
+		}
+
+	case "bool":
+		if required {
+			tag.kind, tag.oneof = "oneof", []string{"true"}
+		}
+
+	case "int", "float":
+		if tag.oneof != nil {
+			tag.kind = "oneof"
+			break
+		}
+
+		defLo, defHi := intRange(base.Kind())
+		if shape == "float" {
+			tag.kind = "float"
+		}
+
+		l, h := bounds(fakeTag{min: lo, max: hi}, float64(defLo), float64(defHi))
+		if required && l <= 0 && h >= 0 {
+			// Keep zero out of the range.
+			if h >= 1 {
+				l = 1
+			} else if l <= -1 {
+				h = -1
+			}
+		}
+		tag.min, tag.max = &l, &h
+
+	case "container":
+		// At least one element, so the rules after dive apply to something.
+		tag.minLen, tag.maxLen = lenRange(lo, hi, 1, defaultLen, true)
+
+		var rest [][]validateRule
+		if len(levels) > 1 {
+			rest = levels[1:]
+		}
+		elem, err := validateFakeTag(base.Elem(), rest)
+		if err != nil {
+			return tag, err
+		}
+		tag.elem = &elem
+	}
+
+	return tag, nil
+}
+
+// validateShape groups types by the validate rules they support.
+func validateShape(t reflect.Type) string {
+	switch {
+	case t == timeType:
+		return "time"
+	case t.Kind() == reflect.String:
+		return "string"
+	case t.Kind() == reflect.Bool:
+		return "bool"
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uintptr:
+		return "int"
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return "float"
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map:
+		return "container"
+	case t.Kind() == reflect.Struct:
+		return "struct"
+	default:
+		return ""
+	}
+}
+
+// lenRange returns the length range allowed by the min and max rules, or the
+// defaults. nonEmpty leaves out length zero when the range allows more.
+func lenRange(lo, hi *float64, defLo, defHi int, nonEmpty bool) (int, int) {
+	l, h := bounds(fakeTag{min: lo, max: hi}, float64(defLo), float64(defHi))
+
+	minLen := max(int(math.Ceil(l)), 0)
+	maxLen := max(int(math.Floor(h)), minLen)
+	if nonEmpty && minLen == 0 && maxLen > 0 {
+		minLen = 1
+	}
+
+	return minLen, maxLen
+}
+
+// oneOfValues splits the values of a validate oneof rule.
+func oneOfValues(param string) []string {
+	values := oneOfValue.FindAllString(param, -1)
+	for i, v := range values {
+		values[i] = strings.Trim(v, "'")
+	}
+	return values
+}
+
+// breaker finds the rules GenerateInvalidFakes can break, or breaks its target.
+type breaker struct {
+	target *brokenRule
+	hit    bool
+	found  []brokenRule
+}
+
+type brokenRule struct {
+	field, rule string
+}
+
+// visit is called with each generated value that has validate rules.
+func (b *breaker) visit(g *generator, v reflect.Value, tag fakeTag) {
+	if g.skip > 0 {
+		return
+	}
+	field := strings.Join(g.path, ".")
+
+	for _, r := range tag.rules {
+		if b.target != nil && (b.target.field != field || b.target.rule != r.raw) {
+			continue
+		}
+
+		broken, ok := g.breakRule(v, tag, r)
+		// omitempty accepts an empty value whatever the other rules say.
+		if !ok || tag.omitempty && isEmpty(broken) {
+			continue
+		}
+
+		if b.target == nil {
+			b.found = append(b.found, brokenRule{field: field, rule: r.raw})
+			continue
+		}
+
+		v.Set(broken)
+		b.hit = true
+		return
+	}
+}
+
+// breakRule returns a copy of v that breaks r, or false if r cannot be broken.
+func (g *generator) breakRule(v reflect.Value, tag fakeTag, r validateRule) (reflect.Value, bool) {
+	if r.name == "required" {
+		// The validator does not check required on struct values.
+		if v.Kind() == reflect.Struct && v.Type() != timeType {
+			return v, false
+		}
+		return reflect.Zero(v.Type()), true
+	}
+
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return v, false
+		}
+		elem, ok := g.breakRule(v.Elem(), tag, r)
+		if !ok {
+			return v, false
+		}
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(elem)
+		return p, true
+	}
+
+	switch r.name {
+	case "email", "uuid", "url":
+		if v.Kind() != reflect.String {
+			return v, false
+		}
+		return reflect.ValueOf(invalidFormats[r.name]).Convert(v.Type()), true
+
+	case "min", "gte", "max", "lte", "len":
+		n, err := strconv.ParseFloat(r.param, 64)
+		if err != nil {
+			return v, false
+		}
+		if r.name == "min" || r.name == "gte" {
+			return g.resize(v, n-1)
+		}
+		return g.resize(v, n+1)
+
+	case "oneof":
+		values := oneOfValues(r.param)
+		if v.Kind() == reflect.String {
+			s := "invalid"
+			for slices.Contains(values, s) {
+				s += "x"
+			}
+			return reflect.ValueOf(s).Convert(v.Type()), true
+		}
+
+		// One more than the largest value is none of them.
+		largest := math.Inf(-1)
+		for _, s := range values {
+			n, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return v, false
+			}
+			largest = max(largest, n)
+		}
+		return g.resize(v, math.Floor(largest)+1)
+	}
+
+	return v, false
+}
+
+// resize returns a copy of v with length n, or with value n for numbers.
+func (g *generator) resize(v reflect.Value, n float64) (reflect.Value, bool) {
+	out := reflect.New(v.Type()).Elem()
+
+	switch {
+	case v.CanInt():
+		if math.IsInf(n, 0) || out.OverflowInt(int64(n)) {
+			return v, false
+		}
+		out.SetInt(int64(n))
+		return out, true
+	case v.CanUint():
+		if n < 0 || math.IsInf(n, 0) || out.OverflowUint(uint64(n)) {
+			return v, false
+		}
+		out.SetUint(uint64(n))
+		return out, true
+	case v.CanFloat():
+		out.SetFloat(n)
+		return out, true
+	}
+
+	if n < 0 {
+		return v, false
+	}
+	size := int(n)
+
+	switch v.Kind() {
+	case reflect.String:
+		out.SetString(g.f.RandString(size))
+
+	case reflect.Slice:
+		out = reflect.MakeSlice(v.Type(), size, size)
+		for i := range size {
+			if v.Len() > 0 {
+				out.Index(i).Set(v.Index(i % v.Len()))
+			}
+		}
+
+	case reflect.Map:
+		out = reflect.MakeMapWithSize(v.Type(), size)
+		value := reflect.New(v.Type().Elem()).Elem()
+		for iter := v.MapRange(); out.Len() < size && iter.Next(); {
+			out.SetMapIndex(iter.Key(), iter.Value())
+			value = iter.Value()
+		}
+
+		// Growing the map needs new keys.
+		keyTag := newFakeTag(defaultKind(v.Type().Key()))
+		for range 10 * size {
+			if out.Len() == size {
+				break
+			}
+			key := reflect.New(v.Type().Key()).Elem()
+			if _, err := g.fill(key, keyTag); err != nil {
+				return v, false
+			}
+			if !out.MapIndex(key).IsValid() {
+				out.SetMapIndex(key, value)
+			}
+		}
+		if out.Len() < size {
+			return v, false
+		}
+
+	default:
+		return v, false
+	}
+
+	return out, true
+}
+
+// isEmpty reports whether v is zero, or a pointer to zero, which omitempty skips.
+func isEmpty(v reflect.Value) bool {
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,202 @@
+package faker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shoraid/stx-go-utils/apperror"
+	"github.com/shoraid/stx-go-utils/faker"
+	"github.com/shoraid/stx-go-utils/structutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SignupLine struct {
+	SKU string `json:"sku" validate:"required,len=6"`
+	Qty uint8  `json:"qty" validate:"required,min=1,max=10"`
+}
+
+type SignupRequest struct {
+	ID        string            `json:"id" validate:"required,uuid"`
+	Email     string            `json:"email" validate:"required,email,max=100"`
+	Website   *string           `json:"website" validate:"omitempty,url"`
+	Name      string            `json:"name" validate:"required,min=2,max=5"`
+	Plan      string            `json:"plan" validate:"required,oneof=free pro 'pro plus'"`
+	Level     int               `json:"level" validate:"oneof=1 2 3"`
+	Age       int               `json:"age" validate:"gte=18,lte=120"`
+	Score     float64           `json:"score" validate:"required,min=0,max=1"`
+	Accepted  bool              `json:"accepted" validate:"required"`
+	BirthDate time.Time         `json:"birthDate" validate:"required"`
+	Tags      []string          `json:"tags" validate:"required,max=3,dive,min=2,max=10"`
+	Lines     []SignupLine      `json:"lines" validate:"min=1,dive"`
+	Labels    map[string]string `json:"labels" validate:"max=2"`
+	Nickname  string            `json:"nickname" faker:"string,len=4" validate:"max=4"`
+	Ignored   string            `json:"ignored" validate:"-"`
+}
+
+func TestFaker_SetValidateTags(t *testing.T) {
+	f := faker.New(3)
+	f.SetValidateTags(true)
+
+	for range 50 {
+		req, err := faker.TryGenerateFake[SignupRequest](f)
+		require.NoError(t, err)
+
+		fieldErrors, err := structutil.Validate(req)
+		require.NoError(t, err, "fake should pass validation: %v", fieldErrors)
+
+		assert.NotNil(t, req.Website)
+		assert.Contains(t, []string{"free", "pro", "pro plus"}, req.Plan)
+		assert.True(t, req.Accepted)
+		assert.NotEmpty(t, req.Lines)
+		assert.Len(t, req.Nickname, 4, "faker tag wins over validate tag")
+		assert.Empty(t, req.Ignored)
+	}
+}
+
+func TestFaker_SetValidateTags_Off(t *testing.T) {
+	req := faker.GenerateFakeWith[SignupRequest](faker.New(3))
+
+	assert.Empty(t, req.Email)
+	assert.Empty(t, req.Tags)
+	assert.Len(t, req.Nickname, 4)
+}
+
+func TestFaker_SetValidateTags_Errors(t *testing.T) {
+	type unsupported struct {
+		Phone string `validate:"required,e164"`
+	}
+	type badNumber struct {
+		Age int `validate:"min=x"`
+	}
+	type crossedRange struct {
+		Name string `validate:"min=5,max=2"`
+	}
+	type diveOnString struct {
+		Name string `validate:"dive,required"`
+	}
+	type unsupportedInElement struct {
+		Phones []string `validate:"dive,e164"`
+	}
+
+	tests := []struct {
+		name        string
+		generate    func(f *faker.Faker) error
+		expectedErr string
+	}{
+		{
+			name:        "unsupported rule",
+			generate:    func(f *faker.Faker) error { _, err := faker.TryGenerateFake[unsupported](f); return err },
+			expectedErr: `faker: field unsupported.Phone: tag "required,e164": validate rule e164 is not supported for a string field`,
+		},
+		{
+			name:        "bad number",
+			generate:    func(f *faker.Faker) error { _, err := faker.TryGenerateFake[badNumber](f); return err },
+			expectedErr: `faker: field badNumber.Age: tag "min=x": min must be a number, got "x"`,
+		},
+		{
+			name:        "min greater than max",
+			generate:    func(f *faker.Faker) error { _, err := faker.TryGenerateFake[crossedRange](f); return err },
+			expectedErr: `faker: field crossedRange.Name: tag "min=5,max=2": min 5 is greater than max 2`,
+		},
+		{
+			name:        "dive on a scalar",
+			generate:    func(f *faker.Faker) error { _, err := faker.TryGenerateFake[diveOnString](f); return err },
+			expectedErr: `faker: field diveOnString.Name: tag "dive,required": dive needs a slice, array or map field, not string`,
+		},
+		{
+			name:        "unsupported rule after dive",
+			generate:    func(f *faker.Faker) error { _, err := faker.GenerateInvalidFakes[unsupportedInElement](f); return err },
+			expectedErr: `faker: field unsupportedInElement.Phones: tag "dive,e164": validate rule e164 is not supported for a string field`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := faker.New(1)
+			f.SetValidateTags(true)
+
+			err := tt.generate(f)
+
+			var tagErr *faker.TagError
+			require.ErrorAs(t, err, &tagErr)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestFaker_GenerateInvalidFakes(t *testing.T) {
+	fakes, err := faker.GenerateInvalidFakes[SignupRequest](faker.New(8))
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"SignupRequest.ID required":                    "id",
+		"SignupRequest.ID uuid":                        "id",
+		"SignupRequest.Email required":                 "email",
+		"SignupRequest.Email email":                    "email",
+		"SignupRequest.Email max=100":                  "email",
+		"SignupRequest.Website url":                    "website",
+		"SignupRequest.Name required":                  "name",
+		"SignupRequest.Name min=2":                     "name",
+		"SignupRequest.Name max=5":                     "name",
+		"SignupRequest.Plan required":                  "plan",
+		"SignupRequest.Plan oneof=free pro 'pro plus'": "plan",
+		"SignupRequest.Level oneof=1 2 3":              "level",
+		"SignupRequest.Age gte=18":                     "age",
+		"SignupRequest.Age lte=120":                    "age",
+		"SignupRequest.Score required":                 "score",
+		"SignupRequest.Score min=0":                    "score",
+		"SignupRequest.Score max=1":                    "score",
+		"SignupRequest.Accepted required":              "accepted",
+		"SignupRequest.BirthDate required":             "birthDate",
+		"SignupRequest.Tags[0] min=2":                  "tags.0",
+		"SignupRequest.Tags[0] max=10":                 "tags.0",
+		"SignupRequest.Tags required":                  "tags",
+		"SignupRequest.Tags max=3":                     "tags",
+		"SignupRequest.Lines[0].SKU required":          "lines.0.sku",
+		"SignupRequest.Lines[0].SKU len=6":             "lines.0.sku",
+		"SignupRequest.Lines[0].Qty required":          "lines.0.qty",
+		"SignupRequest.Lines[0].Qty min=1":             "lines.0.qty",
+		"SignupRequest.Lines[0].Qty max=10":            "lines.0.qty",
+		"SignupRequest.Lines min=1":                    "lines",
+		"SignupRequest.Labels max=2":                   "labels",
+		"SignupRequest.Nickname max=4":                 "nickname",
+	}
+
+	got := make([]string, 0, len(fakes))
+	for _, fake := range fakes {
+		name := fake.Field + " " + fake.Rule
+		got = append(got, name)
+
+		t.Run(name, func(t *testing.T) {
+			fieldErrors, err := structutil.Validate(fake.Value)
+			require.ErrorIs(t, err, apperror.Err400InvalidData)
+
+			key := expected[name]
+			assert.Contains(t, fieldErrors, key)
+			assert.Len(t, fieldErrors, 1, "only %s should be invalid: %v", key, fieldErrors)
+		})
+	}
+
+	wanted := make([]string, 0, len(expected))
+	for name := range expected {
+		wanted = append(wanted, name)
+	}
+	assert.ElementsMatch(t, wanted, got)
+}
+
+func TestFaker_GenerateInvalidFakes_NotStruct(t *testing.T) {
+	fakes, err := faker.GenerateInvalidFakes[[]string](faker.New(1))
+
+	require.NoError(t, err)
+	assert.Empty(t, fakes)
+}
+
+func BenchmarkGenerateFake_ValidateTags(b *testing.B) {
+	f := faker.New(42)
+	f.SetValidateTags(true)
+
+	for b.Loop() {
+		faker.GenerateFakeWith[SignupRequest](f)
+	}
+}
@@ -2,6 +2,7 @@ package httpresponse
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/shoraid/stx-go-utils/apperror"
@@ -26,8 +27,8 @@ func HandleError(w http.ResponseWriter, err error, details ...any) bool {
 	var resp Response
 	var statusCode int
 
-	switch err {
-	case apperror.Err400InvalidAction:
+	switch {
+	case errors.Is(err, apperror.Err400InvalidAction):
 		resp = Response{
 			Code:    apperror.INVALID_ACTION_CODE,
 			Message: "Invalid action",
@@ -35,7 +36,7 @@ func HandleError(w http.ResponseWriter, err error, details ...any) bool {
 		}
 		statusCode = http.StatusBadRequest
 
-	case apperror.Err400InvalidData:
+	case errors.Is(err, apperror.Err400InvalidData):
 		resp = Response{
 			Code:    apperror.INVALID_DATA_CODE,
 			Message: "Invalid data",
@@ -43,7 +44,7 @@ func HandleError(w http.ResponseWriter, err error, details ...any) bool {
 		}
 		statusCode = http.StatusBadRequest
 
-	case apperror.Err400InvalidBody:
+	case errors.Is(err, apperror.Err400InvalidBody):
 		resp = Response{
 			Code:    apperror.INVALID_BODY_CODE,
 			Message: "Invalid body",
@@ -51,7 +52,7 @@ func HandleError(w http.ResponseWriter, err error, details ...any) bool {
 		}
 		statusCode = http.StatusBadRequest
 
-	case apperror.Err400InvalidParams:
+	case errors.Is(err, apperror.Err400InvalidParams):
 		resp = Response{
 			Code:    apperror.INVALID_PARAMS_CODE,
 			Message: "Invalid params",
@@ -59,7 +60,7 @@ func HandleError(w http.ResponseWriter, err error, details ...any) bool {
 		}
 		statusCode = http.StatusBadRequest
 
-	case apperror.Err401Unauthorized:
+	case errors.Is(err, apperror.Err401Unauthorized):
 		resp = Response{
 			Code:    apperror.UNAUTHORIZED_CODE,
 			Message: "Unauthorized",
@@ -67,7 +68,7 @@ func HandleError(w http.ResponseWriter, err error, details ...any) bool {
 		}
 		statusCode = http.StatusUnauthorized
 
-	case apperror.Err403Forbidden:
+	case errors.Is(err, apperror.Err403Forbidden):
 		resp = Response{
 			Code:    apperror.FORBIDDEN_CODE,
 			Message: "Forbidden",
@@ -75,7 +76,7 @@ func HandleError(w http.ResponseWriter, err error, details ...any) bool {
 		}
 		statusCode = http.StatusForbidden
 
-	case apperror.Err403NoTenant:
+	case errors.Is(err, apperror.Err403NoTenant):
 		resp = Response{
 			Code:    apperror.FORBIDDEN_NO_TENANT_CODE,
 			Message: "User has no tenant",
@@ -83,7 +84,7 @@ func HandleError(w http.ResponseWriter, err error, details ...any) bool {
 		}
 		statusCode = http.StatusForbidden
 
-	case apperror.Err403CSRFTokenMismatch:
+	case errors.Is(err, apperror.Err403CSRFTokenMismatch):
 		resp = Response{
 			Code:    apperror.CSRF_TOKEN_MISMATCH_CODE,
 			Message: "CSRF token mismatch",
@@ -91,7 +92,7 @@ func HandleError(w http.ResponseWriter, err error, details ...any) bool {
 		}
 		statusCode = http.StatusForbidden
 
-	case apperror.Err404RecordNotFound:
+	case errors.Is(err, apperror.Err404RecordNotFound):
 		resp = Response{
 			Code:    apperror.RECORD_NOT_FOUND_CODE,
 			Message: "Record not found",
@@ -99,6 +100,22 @@ func HandleError(w http.ResponseWriter, err error, details ...any) bool {
 		}
 		statusCode = http.StatusNotFound
 
+	case errors.Is(err, apperror.Err429TooManyRequests):
+		resp = Response{
+			Code:    apperror.TOO_MANY_REQUESTS_CODE,
+			Message: "Too many requests",
+			Details: errorDetails,
+		}
+		statusCode = http.StatusTooManyRequests
+
+	case errors.Is(err, apperror.Err503ServiceUnavailable):
+		resp = Response{
+			Code:    apperror.SERVICE_UNAVAILABLE_CODE,
+			Message: "Service unavailable",
+			Details: errorDetails,
+		}
+		statusCode = http.StatusServiceUnavailable
+
 	default:
 		resp = Response{
 			Code:    apperror.INTERNAL_SERVER_ERROR_CODE,
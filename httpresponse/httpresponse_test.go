@@ -3,6 +3,7 @@ package httpresponse_test
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -143,6 +144,39 @@ func TestHttpResponse_HandleError(t *testing.T) {
 			},
 			expectedReturn: true,
 		},
+		{
+			name:         "too many requests should return 429",
+			err:          apperror.Err429TooManyRequests,
+			expectedCode: http.StatusTooManyRequests,
+			expectedBody: map[string]any{
+				"code":    string(apperror.TOO_MANY_REQUESTS_CODE),
+				"message": "Too many requests",
+				"details": nil,
+			},
+			expectedReturn: true,
+		},
+		{
+			name:         "service unavailable should return 503",
+			err:          apperror.Err503ServiceUnavailable,
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: map[string]any{
+				"code":    string(apperror.SERVICE_UNAVAILABLE_CODE),
+				"message": "Service unavailable",
+				"details": nil,
+			},
+			expectedReturn: true,
+		},
+		{
+			name:         "wrapped error should match its sentinel",
+			err:          fmt.Errorf("payments: %w", apperror.Err503ServiceUnavailable),
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: map[string]any{
+				"code":    string(apperror.SERVICE_UNAVAILABLE_CODE),
+				"message": "Service unavailable",
+				"details": nil,
+			},
+			expectedReturn: true,
+		},
 		{
 			name:         "default error should return 500",
 			err:          errors.New("default error"),
@@ -231,6 +265,14 @@ func BenchmarkHttpResponse_HandleError(b *testing.B) {
 			name: "InternalServerError",
 			err:  apperror.Err500InternalServer,
 		},
+		{
+			name: "TooManyRequestsError",
+			err:  apperror.Err429TooManyRequests,
+		},
+		{
+			name: "ServiceUnavailableError",
+			err:  apperror.Err503ServiceUnavailable,
+		},
 		{
 			name: "DefaultError",
 			err:  errors.New("default error"),
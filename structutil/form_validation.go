@@ -1,6 +1,7 @@
 package structutil
 
 import (
+	"context"
 	"encoding/json"
 	"mime/multipart"
 	"net/http"
@@ -8,7 +9,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/shoraid/stx-go-utils/apperror"
 )
 
@@ -22,6 +22,10 @@ import (
 // Returns:
 // - error: binding error if form parsing fails or type conversion fails.
 //
+// `default` and `mod` tags are applied after binding, see Normalize. Like
+// Normalize, BindForm panics on a struct type with a `default` tag on a bool or
+// numeric field that is not a pointer, e.g. `PerPage int default:"20"`; use *int.
+//
 // Supported field types:
 // - Scalar: string, int, int64, float64, bool, uint and their pointer variants
 // - Slices: []string, []int, etc.
@@ -57,7 +61,11 @@ func BindForm(r *http.Request, input any) error {
 		}
 	}
 
-	return bindFormValues(r.Form, multipartForm, input)
+	if err := bindFormValues(r.Form, multipartForm, input); err != nil {
+		return err
+	}
+
+	return Normalize(input)
 }
 
 // bindFormValues binds url.Values and file uploads to a struct using reflection
@@ -277,28 +285,26 @@ func setScalarValue(fieldValue reflect.Value, value string) error {
 //	    "age":   {"minimum value is 18"},
 //	}, apperror.Err400InvalidData
 func ValidateForm(input any) (map[string][]string, error) {
-	err := Validator.Struct(input)
-	if err == nil {
-		return nil, nil
-	}
-
-	validationErrors := make(map[string][]string)
-
-	root := reflect.TypeOf(input)
-	if root.Kind() == reflect.Pointer {
-		root = root.Elem()
-	}
-
-	for _, fe := range err.(validator.ValidationErrors) {
-		fieldPath := buildFormPath(root, fe)
-		message := getErrorMessage(fe)
-		validationErrors[fieldPath] = append(validationErrors[fieldPath], message)
-	}
+	return validateStruct(context.Background(), input, false, getFormTagName)
+}
 
-	return validationErrors, apperror.Err400InvalidData
+// ValidateFormCtx validates like ValidateForm and additionally runs the
+// context-aware struct rules registered with RegisterStructRuleCtx.
+//
+// Parameters:
+// - ctx: context passed to the struct rules, e.g. for repository lookups.
+// - input: struct or pointer to struct with `validate` tags.
+//
+// Returns:
+// - map[string][]string: validation errors using form field paths as keys.
+// - error: apperror.Err400InvalidData if validation fails, the rule error if a rule
+// fails to run, nil if valid.
+func ValidateFormCtx(ctx context.Context, input any) (map[string][]string, error) {
+	return validateStruct(ctx, input, true, getFormTagName)
 }
 
 // BindAndValidateForm binds form data to a struct and validates it.
+// Bound values are sanitized with Sanitize before validation.
 //
 // Parameters:
 // - r: HTTP request with form data.
@@ -324,6 +330,15 @@ func BindAndValidateForm(r *http.Request, input any) (map[string][]string, error
 		if formErr != nil {
 			return fieldErrors, formErr
 		}
+
+		// Binding stopped early, apply defaults before validating
+		if err := Normalize(input); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := Sanitize(input); err != nil {
+		return nil, err
 	}
 
 	return ValidateForm(input)
@@ -339,53 +354,6 @@ func getFormTagName(field reflect.StructField) string {
 	return name
 }
 
-// buildFormPath builds the form field path from validation error
-func buildFormPath(root reflect.Type, fe validator.FieldError) string {
-	ns := fe.StructNamespace()
-	parts := strings.Split(ns, ".")
-
-	var path []string
-	current := root
-
-	for _, part := range parts {
-		if part == "" {
-			continue
-		}
-
-		// Handle index (slice), e.g. Items[0]
-		if strings.Contains(part, "[") {
-			name := part[:strings.Index(part, "[")]
-			index := part[strings.Index(part, "[")+1 : strings.Index(part, "]")]
-
-			if field, ok := current.FieldByName(name); ok {
-				formKey := getFormTagName(field)
-				path = append(path, formKey+"."+index)
-
-				current = field.Type
-				if current.Kind() == reflect.Slice {
-					current = current.Elem()
-				}
-				if current.Kind() == reflect.Ptr {
-					current = current.Elem()
-				}
-			}
-			continue
-		}
-
-		if field, ok := current.FieldByName(part); ok {
-			formKey := getFormTagName(field)
-			path = append(path, formKey)
-
-			current = field.Type
-			if current.Kind() == reflect.Ptr {
-				current = current.Elem()
-			}
-		}
-	}
-
-	return strings.Join(path, ".")
-}
-
 // getFormErrorMessage converts binding errors to field error maps
 func getFormErrorMessage(err error) (map[string][]string, error) {
 	switch e := err.(type) {
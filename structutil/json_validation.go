@@ -1,6 +1,7 @@
 package structutil
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"reflect"
@@ -29,6 +30,9 @@ var Validator = validator.New()
 // Features:
 // - Uses reflection to get JSON tag names for error keys.
 // - Supports flat fields, nested fields, and slice elements (with index).
+// - Fields of embedded structs without a json name are keyed at the level they
+// are promoted to, like encoding/json decodes them.
+// - Runs struct rules registered with RegisterStructRule after field validation.
 //
 // Example:
 //
@@ -67,11 +71,35 @@ var Validator = validator.New()
 //	    "permissionIds.0":   {"field must be a valid UUID"},
 //	}, apperror.Err400InvalidData
 func Validate(input any) (map[string][]string, error) {
-	err := Validator.Struct(input)
-	if err == nil {
-		return nil, nil
-	}
+	return validateStruct(context.Background(), input, false, getJSONPathName)
+}
+
+// ValidateCtx validates like Validate and additionally runs the context-aware
+// struct rules registered with RegisterStructRuleCtx.
+//
+// Parameters:
+// - ctx: context passed to the struct rules, e.g. for repository lookups.
+// - input: struct or pointer to struct with `validate` tags.
+//
+// Returns:
+// - map[string][]string: validation errors using JSON field paths as keys.
+// - error: apperror.Err400InvalidData if validation fails, the rule error if a rule
+// fails to run, nil if valid.
+//
+// Example:
+//
+//	fieldErrors, err := ValidateCtx(r.Context(), input)
+//	// Output:
+//	map[string][]string{
+//	    "email": {"email is already taken"},
+//	}, apperror.Err400InvalidData
+func ValidateCtx(ctx context.Context, input any) (map[string][]string, error) {
+	return validateStruct(ctx, input, true, getJSONPathName)
+}
 
+// validateStruct runs field tag validation and struct rules, keying errors with
+// the names returned by tagName.
+func validateStruct(ctx context.Context, input any, withCtx bool, tagName func(reflect.StructField) string) (map[string][]string, error) {
 	validationErrors := make(map[string][]string)
 
 	root := reflect.TypeOf(input)
@@ -79,10 +107,26 @@ func Validate(input any) (map[string][]string, error) {
 		root = root.Elem()
 	}
 
-	for _, fe := range err.(validator.ValidationErrors) {
-		fieldPath := buildJSONPath(root, fe)
-		message := getErrorMessage(fe)
-		validationErrors[fieldPath] = append(validationErrors[fieldPath], message)
+	if err := Validator.Struct(input); err != nil {
+		for _, fe := range err.(validator.ValidationErrors) {
+			fieldPath := buildFieldPath(root, trimRootNamespace(root, fe.StructNamespace()), tagName)
+			message := getErrorMessage(fe)
+			validationErrors[fieldPath] = append(validationErrors[fieldPath], message)
+		}
+	}
+
+	ruleErrors, err := runStructRules(ctx, input, withCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, re := range ruleErrors {
+		fieldPath := buildFieldPath(root, re.field, tagName)
+		validationErrors[fieldPath] = append(validationErrors[fieldPath], re.message)
+	}
+
+	if len(validationErrors) == 0 {
+		return nil, nil
 	}
 
 	return validationErrors, apperror.Err400InvalidData
@@ -96,6 +140,15 @@ func BindAndValidateJSON(r *http.Request, input any) (map[string][]string, error
 		if jsonErr != nil {
 			return fieldErrors, jsonErr
 		}
+
+		// Decoding stopped early (e.g. empty body), apply defaults before validating
+		if err := Normalize(input); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := Sanitize(input); err != nil {
+		return nil, err
 	}
 
 	return Validate(input)
@@ -131,52 +184,103 @@ func getJSONTagName(field reflect.StructField) string {
 	return name
 }
 
-func buildJSONPath(root reflect.Type, fe validator.FieldError) string {
-	ns := fe.StructNamespace() // e.g. "Meta.Note" or "Items[0].Name"
-	parts := strings.Split(ns, ".")
+// getJSONPathName is getJSONTagName for error paths. It returns "" for embedded
+// structs whose fields encoding/json promotes, so they add no path segment.
+func getJSONPathName(field reflect.StructField) string {
+	if isPromotedJSONStruct(field) {
+		return ""
+	}
+	return getJSONTagName(field)
+}
+
+// isPromotedJSONStruct reports whether encoding/json promotes the fields of the
+// embedded struct field instead of decoding it under its own key.
+func isPromotedJSONStruct(field reflect.StructField) bool {
+	if !field.Anonymous || strings.Split(field.Tag.Get("json"), ",")[0] != "" {
+		return false
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Pointer {
+		// encoding/json cannot allocate an unexported embedded pointer
+		if !field.IsExported() {
+			return false
+		}
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct
+}
+
+// trimRootNamespace drops the root struct name from a validator namespace,
+// e.g. "UserRequest.Meta.Note" becomes "Meta.Note". Anonymous structs have no root name.
+func trimRootNamespace(root reflect.Type, ns string) string {
+	if root.Name() == "" {
+		return ns
+	}
+	if ns == root.Name() {
+		return ""
+	}
+	return strings.TrimPrefix(ns, root.Name()+".")
+}
 
+// buildFieldPath converts a Go field namespace relative to root (e.g. "Meta.Note" or
+// "Items[0].Name") into a dotted path using the names returned by tagName
+// (e.g. "meta.note" or "items.0.name"). Parts that do not match a field are kept
+// as-is, and fields tagName names "" are left out of the path.
+func buildFieldPath(root reflect.Type, ns string, tagName func(reflect.StructField) string) string {
 	var path []string
 	current := root
 
-	for _, part := range parts {
+	for _, part := range strings.Split(ns, ".") {
 		if part == "" {
 			continue
 		}
 
 		// Handle index (slice), e.g. Items[0]
+		name, index := part, ""
 		if strings.Contains(part, "[") {
-			name := part[:strings.Index(part, "[")]
-			index := part[strings.Index(part, "[")+1 : strings.Index(part, "]")]
+			name = part[:strings.Index(part, "[")]
+			index = part[strings.Index(part, "[")+1 : strings.Index(part, "]")]
+		}
+
+		key := name
+		var next reflect.Type
 
+		if current != nil && current.Kind() == reflect.Struct {
 			if field, ok := current.FieldByName(name); ok {
-				jsonKey := getJSONTagName(field)
-				path = append(path, jsonKey+"."+index)
-
-				current = field.Type
-				if current.Kind() == reflect.Slice {
-					current = current.Elem()
-				}
-				if current.Kind() == reflect.Ptr {
-					current = current.Elem()
-				}
+				key = tagName(field)
+				next = derefType(field.Type)
 			}
-			continue
 		}
 
-		if field, ok := current.FieldByName(part); ok {
-			jsonKey := getJSONTagName(field)
-			path = append(path, jsonKey)
+		if key == "" && index == "" {
+			current = next
+			continue
+		}
 
-			current = field.Type
-			if current.Kind() == reflect.Ptr {
-				current = current.Elem()
+		if index != "" {
+			key += "." + index
+			if next != nil && (next.Kind() == reflect.Slice || next.Kind() == reflect.Array || next.Kind() == reflect.Map) {
+				next = derefType(next.Elem())
 			}
 		}
+
+		path = append(path, key)
+		current = next
 	}
 
 	return strings.Join(path, ".")
 }
 
+// derefType unwraps pointer types down to their element type.
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
 func getJsonErrorMessage(err error) (map[string][]string, error) {
 	switch e := err.(type) {
 	case *json.SyntaxError:
@@ -125,6 +125,21 @@ func TestStructUtil_Validate(t *testing.T) {
 			},
 			isError: true,
 		},
+		{
+			name: "Embedded struct fields are keyed where JSON promotes them",
+			request: struct {
+				UserRequest
+				Profile UserRequest `json:"profile"`
+			}{
+				UserRequest: UserRequest{Email: "alice@example.com", Age: 25},
+				Profile:     UserRequest{Name: "Alice", Email: "invalid-email", Age: 25},
+			},
+			expected: map[string][]string{
+				"name":          {"field is required"},
+				"profile.email": {"field must be a valid email address"},
+			},
+			isError: true,
+		},
 		{
 			name: "Field with no json tag should fallback to field name",
 			request: struct {
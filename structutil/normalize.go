@@ -0,0 +1,277 @@
+package structutil
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// modifiers maps the names accepted by the `mod` tag to their string transforms.
+var modifiers = map[string]func(string) string{
+	"trim":  strings.TrimSpace,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"title": toTitle,
+}
+
+// defaultTagErrors caches the checkDefaultTags result per struct type.
+var defaultTagErrors sync.Map // reflect.Type -> error
+
+// Normalize applies `default` and `mod` tags to a struct. Supports nested structs,
+// pointers to structs and slices of structs.
+//
+// It is called by BindJSON and BindForm after decoding, so bound input is already
+// normalized by the time it reaches Validate.
+//
+// Parameters:
+// - input: pointer to struct with `default` and/or `mod` tags.
+//
+// Returns:
+// - error: if a default value cannot be converted to the field type or a `mod` tag
+// names an unknown modifier. Inputs other than a pointer to struct are left untouched.
+//
+// Normalize panics the first time it sees a struct type with a `default` tag on a
+// bool or numeric field that is not a pointer, since an explicit false or 0 would be
+// replaced. The type is checked once, whatever the values.
+//
+// Supported tags:
+// - default: value assigned when the field holds its zero value. Slice defaults are
+// comma separated, e.g. `default:"asc,desc"`. Bool and numeric fields must be
+// pointers, e.g. *bool or *int.
+// - mod: comma separated list of string modifiers applied in order. Available
+// modifiers are trim, lower, upper and title. Works on string, *string and []string.
+//
+// Defaults are applied before modifiers, so default values are normalized as well.
+//
+// Example:
+//
+//	type ListUsersRequest struct {
+//	    Email   string `json:"email" mod:"trim,lower"`
+//	    Name    string `json:"name" mod:"trim,title"`
+//	    PerPage *int   `json:"perPage" default:"20"`
+//	    Sort    string `json:"sort" default:"asc"`
+//	}
+//
+//	input := ListUsersRequest{Email: "  John@Example.COM ", Name: "john doe"}
+//	Normalize(&input)
+//	// input: ListUsersRequest{Email: "john@example.com", Name: "John Doe", Sort: "asc"}
+//	// *input.PerPage: 20
+func Normalize(input any) error {
+	v := reflect.ValueOf(input)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil // nothing to normalize (e.g. binding into a map)
+	}
+
+	t := v.Elem().Type()
+	checked, ok := defaultTagErrors.Load(t)
+	if !ok {
+		checked, _ = defaultTagErrors.LoadOrStore(t, checkDefaultTags(t, make(map[reflect.Type]bool)))
+	}
+	if err, _ := checked.(error); err != nil {
+		panic(err)
+	}
+
+	return walkStruct(v.Elem(), normalizeField)
+}
+
+// checkDefaultTags returns an error for the first `default` tag on a bool or
+// numeric field that is not a pointer, in t and the types it nests.
+func checkDefaultTags(t reflect.Type, seen map[reflect.Type]bool) error {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if _, ok := field.Tag.Lookup("default"); ok && isBoolOrNumber(field.Type.Kind()) {
+			name := field.Name
+			if t.Name() != "" {
+				name = t.Name() + "." + name
+			}
+			return fmt.Errorf("structutil: field %s: default needs a pointer type such as *%s, or an explicit %v is overwritten",
+				name, field.Type, reflect.Zero(field.Type).Interface())
+		}
+
+		if err := checkDefaultTags(field.Type, seen); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeField applies the `default` and `mod` tags of a single field.
+func normalizeField(field reflect.StructField, fieldValue reflect.Value) error {
+	if def, ok := field.Tag.Lookup("default"); ok && fieldValue.IsZero() {
+		if err := setDefaultValue(fieldValue, def); err != nil {
+			return fmt.Errorf("structutil: invalid default %q for field %s: %w", def, field.Name, err)
+		}
+	}
+
+	return modifyField(field, fieldValue)
+}
+
+// isBoolOrNumber reports whether kind is a bool or numeric kind, whose zero value is
+// also a value a client may send.
+func isBoolOrNumber(kind reflect.Kind) bool {
+	return kind == reflect.Bool || kind >= reflect.Int && kind <= reflect.Complex128
+}
+
+// modifyField applies the `mod` tag of a single field.
+func modifyField(field reflect.StructField, fieldValue reflect.Value) error {
+	if mod := field.Tag.Get("mod"); mod != "" {
+		if err := applyModifiers(fieldValue, mod); err != nil {
+			return fmt.Errorf("structutil: field %s: %w", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// walkStruct calls fn for every settable field of v, then recurses into nested
+// structs, pointers to structs and slices of structs.
+func walkStruct(v reflect.Value, fn func(field reflect.StructField, fieldValue reflect.Value) error) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := v.Field(i)
+
+		if !fieldValue.CanSet() {
+			continue
+		}
+
+		if err := fn(field, fieldValue); err != nil {
+			return err
+		}
+
+		if err := walkNested(fieldValue, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// walkNested recurses into struct, pointer to struct and slice of struct values.
+func walkNested(v reflect.Value, fn func(field reflect.StructField, fieldValue reflect.Value) error) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		return walkStruct(v, fn)
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return walkNested(v.Elem(), fn)
+
+	case reflect.Slice, reflect.Array:
+		elemKind := v.Type().Elem().Kind()
+		if elemKind != reflect.Struct && elemKind != reflect.Pointer {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := walkNested(v.Index(i), fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// setDefaultValue converts the `default` tag value to the field type and assigns it.
+func setDefaultValue(fieldValue reflect.Value, def string) error {
+	values := []string{def}
+
+	fieldType := fieldValue.Type()
+	if fieldType.Kind() == reflect.Slice {
+		values = strings.Split(def, ",")
+	}
+
+	return setFieldValue(fieldValue, values)
+}
+
+// applyModifiers runs the modifiers listed in the `mod` tag on a string field.
+func applyModifiers(fieldValue reflect.Value, mod string) error {
+	fns := make([]func(string) string, 0)
+
+	for _, name := range strings.Split(mod, ",") {
+		name = strings.TrimSpace(name)
+		fn, ok := modifiers[name]
+		if !ok {
+			return fmt.Errorf("unknown modifier %q", name)
+		}
+		fns = append(fns, fn)
+	}
+
+	transformStrings(fieldValue, chainStrings(fns))
+
+	return nil
+}
+
+// chainStrings composes string transforms so they run in order.
+func chainStrings(fns []func(string) string) func(string) string {
+	return func(s string) string {
+		for _, fn := range fns {
+			s = fn(s)
+		}
+		return s
+	}
+}
+
+// transformStrings applies fn to a string, *string or []string value.
+// Values of any other kind are left untouched.
+func transformStrings(v reflect.Value, fn func(string) string) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(fn(v.String()))
+
+	case reflect.Pointer:
+		if v.IsNil() || v.Elem().Kind() != reflect.String {
+			return
+		}
+		v.Elem().SetString(fn(v.Elem().String()))
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			v.Index(i).SetString(fn(v.Index(i).String()))
+		}
+	}
+}
+
+// toTitle upper-cases the first letter of every space separated word.
+func toTitle(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	atWordStart := true
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			atWordStart = true
+			b.WriteRune(r)
+			continue
+		}
+
+		if atWordStart {
+			r = unicode.ToTitle(r)
+			atWordStart = false
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
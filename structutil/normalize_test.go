@@ -0,0 +1,249 @@
+package structutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/shoraid/stx-go-utils/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructUtil_Normalize(t *testing.T) {
+	type Address struct {
+		City    string `json:"city" mod:"trim,title"`
+		Country string `json:"country" default:"ID" mod:"upper"`
+	}
+
+	type Request struct {
+		Email     string    `json:"email" mod:"trim,lower"`
+		Nickname  *string   `json:"nickname" mod:"trim"`
+		Tags      []string  `json:"tags" mod:"trim,lower"`
+		PerPage   *int      `json:"perPage" default:"20"`
+		Sort      string    `json:"sort" default:"asc"`
+		Active    *bool     `json:"active" default:"true"`
+		Statuses  []string  `json:"statuses" default:"active,pending"`
+		Address   Address   `json:"address"`
+		Addresses []Address `json:"addresses"`
+		Primary   *Address  `json:"primary"`
+	}
+
+	nickname := "  johnny  "
+	active := false
+	perPage := 50
+
+	tests := []struct {
+		name     string
+		input    Request
+		expected Request
+	}{
+		{
+			name: "applies defaults and modifiers",
+			input: Request{
+				Email:     "  John@Example.COM ",
+				Nickname:  &nickname,
+				Tags:      []string{" Go ", "API"},
+				Address:   Address{City: " new york "},
+				Addresses: []Address{{City: "jakarta", Country: "id"}},
+				Primary:   &Address{City: "bandung"},
+			},
+			expected: Request{
+				Email:     "john@example.com",
+				Nickname:  func() *string { s := "johnny"; return &s }(),
+				Tags:      []string{"go", "api"},
+				PerPage:   func() *int { n := 20; return &n }(),
+				Sort:      "asc",
+				Active:    func() *bool { b := true; return &b }(),
+				Statuses:  []string{"active", "pending"},
+				Address:   Address{City: "New York", Country: "ID"},
+				Addresses: []Address{{City: "Jakarta", Country: "ID"}},
+				Primary:   &Address{City: "Bandung", Country: "ID"},
+			},
+		},
+		{
+			name: "keeps non-zero values",
+			input: Request{
+				PerPage:  &perPage,
+				Sort:     "desc",
+				Active:   &active,
+				Statuses: []string{"archived"},
+				Address:  Address{City: "Paris", Country: "FR"},
+			},
+			expected: Request{
+				PerPage:  &perPage,
+				Sort:     "desc",
+				Active:   &active,
+				Statuses: []string{"archived"},
+				Address:  Address{City: "Paris", Country: "FR"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Normalize(&tt.input)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, tt.input)
+		})
+	}
+}
+
+func TestStructUtil_Normalize_InvalidTags(t *testing.T) {
+	t.Run("invalid default", func(t *testing.T) {
+		var input struct {
+			PerPage *int `default:"twenty"`
+		}
+
+		err := Normalize(&input)
+		assert.ErrorContains(t, err, `invalid default "twenty" for field PerPage`)
+	})
+
+	t.Run("default on a bool field", func(t *testing.T) {
+		var input struct {
+			Active bool `default:"true"`
+		}
+
+		assert.PanicsWithError(t, "structutil: field Active: default needs a pointer type such as *bool, or an explicit false is overwritten", func() {
+			Normalize(&input)
+		})
+	})
+
+	t.Run("default on a nested numeric field", func(t *testing.T) {
+		type page struct {
+			PerPage int `default:"20"`
+		}
+		input := struct {
+			Pages []page
+		}{}
+
+		assert.PanicsWithError(t, "structutil: field page.PerPage: default needs a pointer type such as *int, or an explicit 0 is overwritten", func() {
+			Normalize(&input)
+		})
+	})
+
+	t.Run("unknown modifier", func(t *testing.T) {
+		var input struct {
+			Name string `mod:"trim,shout"`
+		}
+
+		err := Normalize(&input)
+		assert.ErrorContains(t, err, `unknown modifier "shout"`)
+	})
+
+	t.Run("non struct input is ignored", func(t *testing.T) {
+		input := map[string]any{}
+
+		assert.NoError(t, Normalize(&input))
+		assert.NoError(t, Normalize(nil))
+	})
+}
+
+func TestStructUtil_BindAndValidateJSON_Normalize(t *testing.T) {
+	type Request struct {
+		Email   string `json:"email" validate:"required,email" mod:"trim,lower"`
+		PerPage *int   `json:"perPage" default:"20" validate:"max=100"`
+		Active  *bool  `json:"active" default:"true"`
+	}
+
+	perPage := func(n int) *int { return &n }
+	active := func(b bool) *bool { return &b }
+
+	tests := []struct {
+		name           string
+		body           string
+		expected       Request
+		expectedError  error
+		expectedFields map[string][]string
+	}{
+		{
+			name:     "normalized before validation",
+			body:     `{"email":"  John@Example.COM "}`,
+			expected: Request{Email: "john@example.com", PerPage: perPage(20), Active: active(true)},
+		},
+		{
+			name:     "explicit zero values are kept",
+			body:     `{"email":"a@b.com","perPage":0,"active":false}`,
+			expected: Request{Email: "a@b.com", PerPage: perPage(0), Active: active(false)},
+		},
+		{
+			name:          "defaults applied on empty body",
+			body:          ``,
+			expected:      Request{PerPage: perPage(20), Active: active(true)},
+			expectedError: apperror.Err400InvalidData,
+			expectedFields: map[string][]string{
+				"email": {"field is required"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+
+			var input Request
+			result, err := BindAndValidateJSON(req, &input)
+
+			assert.Equal(t, tt.expected, input)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Equal(t, tt.expectedFields, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Nil(t, result)
+			}
+		})
+	}
+}
+
+func TestStructUtil_BindAndValidateForm_Normalize(t *testing.T) {
+	type Request struct {
+		Email string `form:"email" validate:"required,email" mod:"trim,lower"`
+		Sort  string `form:"sort" default:"asc" validate:"oneof=asc desc"`
+	}
+
+	formData := url.Values{"email": {"  Jane@Example.com"}}
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(formData.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var input Request
+	result, err := BindAndValidateForm(req, &input)
+
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, Request{Email: "jane@example.com", Sort: "asc"}, input)
+}
+
+func TestStructUtil_toTitle(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"john doe", "John Doe"},
+		{"  multiple   spaces ", "  Multiple   Spaces "},
+		{"élan vital", "Élan Vital"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, toTitle(tt.input))
+		})
+	}
+}
+
+func BenchmarkStructutil_Normalize(b *testing.B) {
+	type Request struct {
+		Email   string   `json:"email" mod:"trim,lower"`
+		Name    string   `json:"name" mod:"trim,title"`
+		Tags    []string `json:"tags" mod:"trim"`
+		PerPage *int     `json:"perPage" default:"20"`
+	}
+
+	for b.Loop() {
+		input := Request{Email: " John@Example.com ", Name: "john doe", Tags: []string{" a ", " b "}}
+		Normalize(&input)
+	}
+}
@@ -0,0 +1,359 @@
+package structutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/shoraid/stx-go-utils/apperror"
+)
+
+type presenceKind uint8
+
+const (
+	presentValue presenceKind = iota
+	presentNull
+	presentObject
+	presentArray
+)
+
+// Presence records which JSON keys were present in a PATCH payload.
+// Paths use the same dotted format as Validate error keys, e.g. "name", "meta.note".
+// Nested objects are tracked key by key, arrays are tracked as a single value.
+type Presence struct {
+	paths map[string]presenceKind
+}
+
+// Has reports whether path was present in the payload, including explicit nulls.
+func (p Presence) Has(path string) bool {
+	_, ok := p.paths[path]
+	return ok
+}
+
+// IsNull reports whether path was present in the payload with a null value.
+func (p Presence) IsNull(path string) bool {
+	kind, ok := p.paths[path]
+	return ok && kind == presentNull
+}
+
+// Paths returns every present path in sorted order.
+func (p Presence) Paths() []string {
+	paths := make([]string, 0, len(p.paths))
+	for path := range p.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// covers reports whether validation errors on path should be reported: either the
+// path itself was sent, or it lives inside an array, scalar or null that was sent
+// as a whole.
+func (p Presence) covers(path string) bool {
+	if path == "" || p.Has(path) {
+		return true
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] != '.' {
+			continue
+		}
+		if kind, ok := p.paths[path[:i]]; ok && kind != presentObject {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BindJSONPatch decodes a PATCH request body into input and records which keys
+// were present in the payload.
+//
+// Unlike BindJSON, `default` tags are not applied, since filling absent fields
+// would turn them into updates. `mod` tags are still applied.
+//
+// Parameters:
+// - r: HTTP request with a JSON body.
+// - input: pointer to struct, usually with pointer fields so null can be told apart from a value.
+//
+// Returns:
+// - Presence: keys present in the payload, using JSON field paths.
+// - error: decoding error, or apperror.Err400InvalidBody if the body is missing.
+//
+// Example:
+//
+//	type UpdateUserRequest struct {
+//	    Name  *string `json:"name" validate:"required,max=100"`
+//	    Email *string `json:"email" validate:"required,email"`
+//	    Bio   *string `json:"bio"`
+//	}
+//
+//	// body: {"name":"John","bio":null}
+//	presence, err := BindJSONPatch(r, &input)
+//	presence.Has("name")   // true
+//	presence.Has("email")  // false
+//	presence.IsNull("bio") // true
+func BindJSONPatch(r *http.Request, input any) (Presence, error) {
+	presence := Presence{paths: make(map[string]presenceKind)}
+
+	if r.Body == nil {
+		return presence, apperror.Err400InvalidBody
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return presence, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(input); err != nil {
+		return presence, err
+	}
+
+	var raw any
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&raw); err != nil {
+		return presence, err
+	}
+	collectPresence(reflect.TypeOf(input), "", raw, presence.paths)
+
+	v := reflect.ValueOf(input)
+	if v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		if err := walkStruct(v.Elem(), modifyField); err != nil {
+			return presence, err
+		}
+	}
+
+	return presence, nil
+}
+
+// ValidatePatch validates a struct like Validate, but only reports errors for
+// fields present in the payload. Values sent as a whole (arrays, scalars, null)
+// are validated completely, so a present array still validates every element.
+//
+// Parameters:
+// - input: struct or pointer to struct with `validate` tags.
+// - presence: keys present in the payload, as returned by BindJSONPatch.
+//
+// Returns:
+// - map[string][]string: validation errors using JSON field paths as keys.
+// - error: apperror.Err400InvalidData if validation fails, nil if valid.
+//
+// Example:
+//
+//	// body: {"email":"not-an-email","bio":null}
+//	ValidatePatch(input, presence)
+//	// Output:
+//	map[string][]string{
+//	    "email": {"field must be a valid email address"},
+//	}, apperror.Err400InvalidData
+//	// "name" is absent, so its `required` rule is skipped.
+func ValidatePatch(input any, presence Presence) (map[string][]string, error) {
+	fieldErrors, err := Validate(input)
+	if err == nil {
+		return nil, nil
+	}
+
+	for path := range fieldErrors {
+		if !presence.covers(path) {
+			delete(fieldErrors, path)
+		}
+	}
+
+	if len(fieldErrors) == 0 {
+		return nil, nil
+	}
+
+	return fieldErrors, err
+}
+
+// BindAndValidateJSONPatch binds a PATCH request body, sanitizes it and validates
+// only the fields present in the payload.
+//
+// Returns:
+// - Presence: keys present in the payload, for building partial UPDATE statements.
+// - map[string][]string: binding or validation errors using JSON field paths as keys.
+// - error: apperror.Err400InvalidBody if the body is malformed, has unknown fields or cannot be
+// read, apperror.Err400InvalidData if validation fails. An empty body is an empty patch.
+//
+// Example:
+//
+//	presence, fieldErrors, err := BindAndValidateJSONPatch(r, &input)
+//	if httpresponse.HandleError(w, err, fieldErrors) {
+//	    return
+//	}
+//
+//	if presence.Has("name") {
+//	    columns["name"] = input.Name
+//	}
+func BindAndValidateJSONPatch(r *http.Request, input any) (Presence, map[string][]string, error) {
+	presence, err := BindJSONPatch(r, input)
+	if err != nil && !errors.Is(err, io.EOF) {
+		fieldErrors, jsonErr := getJsonErrorMessage(err)
+		if jsonErr != nil {
+			return presence, fieldErrors, jsonErr
+		}
+
+		// Any other failure leaves presence incomplete, so validating would
+		// skip fields that were bound.
+		return presence, nil, apperror.Err400InvalidBody
+	}
+
+	if err := Sanitize(input); err != nil {
+		return presence, nil, err
+	}
+
+	fieldErrors, err := ValidatePatch(input, presence)
+	return presence, fieldErrors, err
+}
+
+// collectPresence walks a decoded JSON value alongside the Go type it was decoded
+// into and records every key path. Keys are stored using the JSON tag name of the
+// matching struct field, so paths line up with Validate error keys.
+func collectPresence(t reflect.Type, prefix string, value any, paths map[string]presenceKind) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if prefix != "" {
+			paths[prefix] = presentObject
+		}
+
+		for key, child := range v {
+			name := key
+			var childType reflect.Type
+
+			if t != nil {
+				switch t.Kind() {
+				case reflect.Struct:
+					if field, ok := findJSONField(t, key); ok {
+						name = getJSONTagName(field)
+						childType = field.Type
+					}
+				case reflect.Map:
+					childType = t.Elem()
+				}
+			}
+
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+			collectPresence(childType, name, child, paths)
+		}
+
+	case []any:
+		paths[prefix] = presentArray
+
+	case nil:
+		paths[prefix] = presentNull
+
+	default:
+		paths[prefix] = presentValue
+	}
+}
+
+// findJSONField finds the struct field a JSON key decodes into. Like encoding/json,
+// an exact tag match wins over a case-insensitive one.
+func findJSONField(t reflect.Type, key string) (reflect.StructField, bool) {
+	var fold reflect.StructField
+	var folded bool
+
+	for _, field := range jsonFields(t) {
+		name := getJSONTagName(field)
+		if name == key {
+			return field, true
+		}
+		if !folded && strings.EqualFold(name, key) {
+			fold, folded = field, true
+		}
+	}
+
+	return fold, folded
+}
+
+// jsonFields returns the fields encoding/json decodes into for t, in field
+// order. Fields of embedded structs are promoted: a shallower field hides a
+// deeper one with the same name, a tagged field wins at the same depth, and
+// any other conflict hides the name altogether.
+func jsonFields(t reflect.Type) []reflect.StructField {
+	type candidate struct {
+		field  reflect.StructField
+		depth  int
+		tagged bool
+	}
+
+	var names []string
+	candidates := make(map[string][]candidate)
+
+	var walk func(t reflect.Type, depth int, seen map[reflect.Type]bool)
+	walk = func(t reflect.Type, depth int, seen map[reflect.Type]bool) {
+		if seen[t] {
+			return
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Tag.Get("json") == "-" {
+				continue
+			}
+
+			if isPromotedJSONStruct(field) {
+				walk(derefType(field.Type), depth+1, seen)
+				continue
+			}
+
+			if !field.IsExported() {
+				continue
+			}
+
+			name := getJSONTagName(field)
+			if _, ok := candidates[name]; !ok {
+				names = append(names, name)
+			}
+			candidates[name] = append(candidates[name], candidate{
+				field:  field,
+				depth:  depth,
+				tagged: strings.Split(field.Tag.Get("json"), ",")[0] != "",
+			})
+		}
+	}
+	walk(t, 0, make(map[reflect.Type]bool))
+
+	fields := make([]reflect.StructField, 0, len(names))
+	for _, name := range names {
+		var dominant []candidate
+		for _, c := range candidates[name] {
+			if len(dominant) == 0 || c.depth < dominant[0].depth {
+				dominant = []candidate{c}
+			} else if c.depth == dominant[0].depth {
+				dominant = append(dominant, c)
+			}
+		}
+
+		var tagged []candidate
+		for _, c := range dominant {
+			if c.tagged {
+				tagged = append(tagged, c)
+			}
+		}
+
+		switch {
+		case len(dominant) == 1:
+			fields = append(fields, dominant[0].field)
+		case len(tagged) == 1:
+			fields = append(fields, tagged[0].field)
+		}
+	}
+
+	return fields
+}
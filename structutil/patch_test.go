@@ -0,0 +1,308 @@
+package structutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/shoraid/stx-go-utils/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+type patchRole struct {
+	ID   string `json:"id" validate:"required,uuid"`
+	Name string `json:"name" validate:"required"`
+}
+
+type patchMeta struct {
+	Note  *string `json:"note" validate:"required"`
+	Color *string `json:"color" validate:"omitempty,oneof=red blue"`
+}
+
+type patchAudit struct {
+	Reason *string `json:"reason" validate:"omitempty,oneof=typo spam"`
+}
+
+type patchUserRequest struct {
+	patchAudit
+	Name   *string     `json:"name" validate:"required,max=10" mod:"trim"`
+	Email  *string     `json:"email" validate:"required,email"`
+	Bio    *string     `json:"bio"`
+	Status *string     `json:"status" default:"active"`
+	Meta   patchMeta   `json:"meta"`
+	Roles  []patchRole `json:"roles" validate:"omitempty,dive"`
+}
+
+func TestStructUtil_BindJSONPatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		paths       []string
+		nulls       []string
+		expectError error
+	}{
+		{
+			name:  "top level keys",
+			body:  `{"name":" John ","bio":null}`,
+			paths: []string{"bio", "name"},
+			nulls: []string{"bio"},
+		},
+		{
+			name:  "nested object keys",
+			body:  `{"meta":{"color":"red"}}`,
+			paths: []string{"meta", "meta.color"},
+		},
+		{
+			name:  "arrays are recorded as a whole",
+			body:  `{"roles":[{"id":"x","name":"Admin"}]}`,
+			paths: []string{"roles"},
+		},
+		{
+			name:  "keys matched case-insensitively use tag names",
+			body:  `{"Email":"a@b.com"}`,
+			paths: []string{"email"},
+		},
+		{
+			name:  "promoted fields of embedded structs",
+			body:  `{"reason":"typo"}`,
+			paths: []string{"reason"},
+		},
+		{
+			name:        "unknown field",
+			body:        `{"unknown":true}`,
+			paths:       []string{},
+			expectError: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/users/1", strings.NewReader(tt.body))
+
+			var input patchUserRequest
+			presence, err := BindJSONPatch(req, &input)
+
+			if tt.expectError != nil {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.paths, presence.Paths())
+			for _, path := range tt.paths {
+				assert.True(t, presence.Has(path))
+			}
+			for _, path := range tt.nulls {
+				assert.True(t, presence.IsNull(path))
+			}
+		})
+	}
+
+	t.Run("applies mod but not default tags", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/users/1", strings.NewReader(`{"name":" John "}`))
+
+		var input patchUserRequest
+		_, err := BindJSONPatch(req, &input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "John", *input.Name)
+		assert.Nil(t, input.Status)
+	})
+
+	t.Run("nil body", func(t *testing.T) {
+		var input patchUserRequest
+		_, err := BindJSONPatch(&http.Request{}, &input)
+
+		assert.ErrorIs(t, err, apperror.Err400InvalidBody)
+	})
+}
+
+func TestStructUtil_BindAndValidateJSONPatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedError  error
+		expectedFields map[string][]string
+	}{
+		{
+			name: "absent required fields are skipped",
+			body: `{"bio":"hello"}`,
+		},
+		{
+			name: "empty body",
+			body: ``,
+		},
+		{
+			name:          "present fields are validated",
+			body:          `{"name":"ThisNameIsWayTooLong","email":"invalid"}`,
+			expectedError: apperror.Err400InvalidData,
+			expectedFields: map[string][]string{
+				"name":  {"maximum length is 10"},
+				"email": {"field must be a valid email address"},
+			},
+		},
+		{
+			name:          "explicit null fails required",
+			body:          `{"email":null}`,
+			expectedError: apperror.Err400InvalidData,
+			expectedFields: map[string][]string{
+				"email": {"field is required"},
+			},
+		},
+		{
+			name:          "nested objects are partial",
+			body:          `{"meta":{"color":"green"}}`,
+			expectedError: apperror.Err400InvalidData,
+			expectedFields: map[string][]string{
+				"meta.color": {"field must be one of: red, blue"},
+			},
+		},
+		{
+			name:          "array elements are fully validated",
+			body:          `{"roles":[{"id":"invalid-uuid"}]}`,
+			expectedError: apperror.Err400InvalidData,
+			expectedFields: map[string][]string{
+				"roles.0.id":   {"field must be a valid UUID"},
+				"roles.0.name": {"field is required"},
+			},
+		},
+		{
+			name:          "promoted fields are validated",
+			body:          `{"reason":"no"}`,
+			expectedError: apperror.Err400InvalidData,
+			expectedFields: map[string][]string{
+				"reason": {"field must be one of: typo, spam"},
+			},
+		},
+		{
+			name:          "unknown field",
+			body:          `{"name":"ThisNameIsWayTooLong","bogus":1}`,
+			expectedError: apperror.Err400InvalidBody,
+		},
+		{
+			name:          "invalid JSON",
+			body:          `{"name":}`,
+			expectedError: apperror.Err400InvalidBody,
+			expectedFields: map[string][]string{
+				"json": {"invalid JSON format: please check for missing commas, braces, or quotes"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/users/1", strings.NewReader(tt.body))
+
+			var input patchUserRequest
+			_, result, err := BindAndValidateJSONPatch(req, &input)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Equal(t, tt.expectedFields, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Nil(t, result)
+			}
+		})
+	}
+
+	t.Run("unreadable body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/users/1", iotest.ErrReader(assert.AnError))
+
+		var input patchUserRequest
+		_, result, err := BindAndValidateJSONPatch(req, &input)
+
+		assert.ErrorIs(t, err, apperror.Err400InvalidBody)
+		assert.Nil(t, result)
+	})
+}
+
+func TestStructUtil_findJSONField(t *testing.T) {
+	type Inner struct {
+		Name  string `json:"name"`
+		Label string
+		Code  string
+	}
+	type Other struct {
+		Code string
+		Kind string `json:"kind"`
+	}
+	type Tagged struct {
+		Label string `json:"Label"`
+	}
+	type outer struct {
+		Inner
+		*Other
+		Tagged
+		Name string `json:"title"`
+		Kind string `json:"kind"`
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		expected string // Go field name, "" if the key is not decoded
+	}{
+		{"promoted field", "name", "Name"},
+		{"outer field", "title", "Name"},
+		{"shallower field hides promoted one", "kind", "Kind"},
+		{"tagged field wins at the same depth", "Label", "Label"},
+		{"untagged conflict hides the key", "Code", ""},
+		{"case-insensitive promoted field", "NAME", "Name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, ok := findJSONField(reflect.TypeFor[outer](), tt.key)
+
+			assert.Equal(t, tt.expected != "", ok)
+			assert.Equal(t, tt.expected, field.Name)
+		})
+	}
+
+	t.Run("tagged winner", func(t *testing.T) {
+		field, _ := findJSONField(reflect.TypeFor[outer](), "Label")
+		assert.Equal(t, `json:"Label"`, string(field.Tag))
+	})
+}
+
+func TestStructUtil_Presence_covers(t *testing.T) {
+	presence := Presence{paths: map[string]presenceKind{
+		"name":       presentValue,
+		"meta":       presentObject,
+		"meta.color": presentValue,
+		"roles":      presentArray,
+		"address":    presentNull,
+	}}
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"", true},
+		{"name", true},
+		{"email", false},
+		{"meta.color", true},
+		{"meta.note", false},
+		{"roles.0.id", true},
+		{"address.city", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, presence.covers(tt.path))
+		})
+	}
+}
+
+func BenchmarkStructutil_BindAndValidateJSONPatch(b *testing.B) {
+	for b.Loop() {
+		req := httptest.NewRequest(http.MethodPatch, "/users/1", strings.NewReader(`{"name":"John","meta":{"color":"red"}}`))
+
+		var input patchUserRequest
+		BindAndValidateJSONPatch(req, &input)
+	}
+}
@@ -0,0 +1,188 @@
+package structutil
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// RuleErrors collects the errors reported by a struct rule.
+type RuleErrors struct {
+	errors []ruleError
+}
+
+type ruleError struct {
+	field   string
+	message string
+}
+
+// Add reports message against field. field is the Go field name relative to the
+// validated struct, dotted for nested fields (e.g. "EndDate", "Address.City",
+// "Items[0].Qty"). It is translated to the JSON or form path in the error map.
+func (e *RuleErrors) Add(field, message string) {
+	e.errors = append(e.errors, ruleError{field: field, message: message})
+}
+
+type structRule struct {
+	withCtx bool
+	fn      func(ctx context.Context, v reflect.Value, errs *RuleErrors) error
+}
+
+var (
+	structRulesMu sync.RWMutex
+	structRules   = map[reflect.Type][]structRule{}
+)
+
+// RegisterStructRule registers a struct-level rule for T. The rule runs during
+// Validate, ValidateForm and their Ctx variants, for the root struct and every
+// nested T (including pointers and slice elements), after field tag validation.
+//
+// T must be a struct type. Errors reported through errs use custom messages and
+// are keyed by the JSON (or form) path of the reported field.
+//
+// Example:
+//
+//	structutil.RegisterStructRule(func(r BookingRequest, errs *structutil.RuleErrors) {
+//	    if !r.EndDate.After(r.StartDate) {
+//	        errs.Add("EndDate", "end date must be after start date")
+//	    }
+//	    if r.Email == "" && r.Phone == "" {
+//	        errs.Add("Email", "email or phone is required")
+//	        errs.Add("Phone", "email or phone is required")
+//	    }
+//	})
+//
+//	Validate(BookingRequest{...})
+//	// Output:
+//	map[string][]string{
+//	    "endDate": {"end date must be after start date"},
+//	    "email":   {"email or phone is required"},
+//	    "phone":   {"email or phone is required"},
+//	}, apperror.Err400InvalidData
+func RegisterStructRule[T any](rule func(input T, errs *RuleErrors)) {
+	registerStructRule[T](structRule{
+		fn: func(_ context.Context, v reflect.Value, errs *RuleErrors) error {
+			rule(v.Interface().(T), errs)
+			return nil
+		},
+	})
+}
+
+// RegisterStructRuleCtx registers a context-aware struct-level rule for T, for
+// checks that need I/O such as uniqueness lookups in a repository. It only runs
+// through ValidateCtx and ValidateFormCtx.
+//
+// A non-nil error returned by the rule aborts validation and is returned as-is,
+// so infrastructure failures are not reported as validation errors.
+//
+// Example:
+//
+//	structutil.RegisterStructRuleCtx(func(ctx context.Context, r CreateUserRequest, errs *structutil.RuleErrors) error {
+//	    exists, err := userRepo.EmailExists(ctx, r.Email)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    if exists {
+//	        errs.Add("Email", "email is already taken")
+//	    }
+//	    return nil
+//	})
+func RegisterStructRuleCtx[T any](rule func(ctx context.Context, input T, errs *RuleErrors) error) {
+	registerStructRule[T](structRule{
+		withCtx: true,
+		fn: func(ctx context.Context, v reflect.Value, errs *RuleErrors) error {
+			return rule(ctx, v.Interface().(T), errs)
+		},
+	})
+}
+
+func registerStructRule[T any](rule structRule) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("structutil: struct rule type must be a struct, got %s", t))
+	}
+
+	structRulesMu.Lock()
+	defer structRulesMu.Unlock()
+
+	structRules[t] = append(structRules[t], rule)
+}
+
+// runStructRules runs the registered rules for every struct found in input and
+// returns the reported errors with field namespaces relative to input.
+func runStructRules(ctx context.Context, input any, withCtx bool) ([]ruleError, error) {
+	structRulesMu.RLock()
+	empty := len(structRules) == 0
+	structRulesMu.RUnlock()
+
+	if empty {
+		return nil, nil
+	}
+
+	var errs []ruleError
+	if err := walkStructRules(ctx, reflect.ValueOf(input), "", withCtx, &errs); err != nil {
+		return nil, err
+	}
+
+	return errs, nil
+}
+
+func walkStructRules(ctx context.Context, v reflect.Value, ns string, withCtx bool, errs *[]ruleError) error {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		structRulesMu.RLock()
+		rules := structRules[v.Type()]
+		structRulesMu.RUnlock()
+
+		for _, rule := range rules {
+			if rule.withCtx && !withCtx {
+				continue
+			}
+
+			reported := &RuleErrors{}
+			if err := rule.fn(ctx, v, reported); err != nil {
+				return err
+			}
+			for _, e := range reported.errors {
+				*errs = append(*errs, ruleError{field: joinNamespace(ns, e.field), message: e.message})
+			}
+		}
+
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if err := walkStructRules(ctx, v.Field(i), joinNamespace(ns, t.Field(i).Name), withCtx, errs); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := walkStructRules(ctx, v.Index(i), fmt.Sprintf("%s[%d]", ns, i), withCtx, errs); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func joinNamespace(ns, field string) string {
+	if ns == "" {
+		return field
+	}
+	if field == "" {
+		return ns
+	}
+	return ns + "." + field
+}
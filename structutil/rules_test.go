@@ -0,0 +1,228 @@
+package structutil
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shoraid/stx-go-utils/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+type ruleContact struct {
+	Email string `json:"email" form:"email_address"`
+	Phone string `json:"phone" form:"phone_number"`
+}
+
+type ruleBooking struct {
+	StartDate time.Time     `json:"startDate" validate:"required"`
+	EndDate   time.Time     `json:"endDate" validate:"required"`
+	Contact   ruleContact   `json:"contact"`
+	Guests    []ruleContact `json:"guests" validate:"dive"`
+}
+
+type ruleSignup struct {
+	Email string `json:"email" form:"email" validate:"required,email"`
+}
+
+var errRuleRepository = errors.New("repository unavailable")
+
+func init() {
+	RegisterStructRule(func(b ruleBooking, errs *RuleErrors) {
+		if !b.StartDate.IsZero() && !b.EndDate.After(b.StartDate) {
+			errs.Add("EndDate", "end date must be after start date")
+		}
+	})
+
+	RegisterStructRule(func(c ruleContact, errs *RuleErrors) {
+		if c.Email == "" && c.Phone == "" {
+			errs.Add("Email", "email or phone is required")
+			errs.Add("Phone", "email or phone is required")
+		}
+	})
+
+	RegisterStructRuleCtx(func(ctx context.Context, s ruleSignup, errs *RuleErrors) error {
+		switch s.Email {
+		case "down@example.com":
+			return errRuleRepository
+		case "taken@example.com":
+			errs.Add("Email", "email is already taken")
+		}
+		return nil
+	})
+}
+
+func TestStructUtil_Validate_StructRules(t *testing.T) {
+	start := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		request  any
+		expected map[string][]string
+		isError  bool
+	}{
+		{
+			name: "Valid booking",
+			request: ruleBooking{
+				StartDate: start,
+				EndDate:   start.Add(24 * time.Hour),
+				Contact:   ruleContact{Email: "john@example.com"},
+			},
+			expected: nil,
+			isError:  false,
+		},
+		{
+			name: "Cross-field rule on root",
+			request: &ruleBooking{
+				StartDate: start,
+				EndDate:   start.Add(-24 * time.Hour),
+				Contact:   ruleContact{Phone: "0812"},
+			},
+			expected: map[string][]string{
+				"endDate": {"end date must be after start date"},
+			},
+			isError: true,
+		},
+		{
+			name: "Rules on nested structs and slice elements",
+			request: ruleBooking{
+				StartDate: start,
+				EndDate:   start.Add(24 * time.Hour),
+				Contact:   ruleContact{},
+				Guests:    []ruleContact{{Email: "a@example.com"}, {}},
+			},
+			expected: map[string][]string{
+				"contact.email":  {"email or phone is required"},
+				"contact.phone":  {"email or phone is required"},
+				"guests.1.email": {"email or phone is required"},
+				"guests.1.phone": {"email or phone is required"},
+			},
+			isError: true,
+		},
+		{
+			name: "Merged with field tag errors",
+			request: ruleBooking{
+				Contact: ruleContact{},
+			},
+			expected: map[string][]string{
+				"startDate":     {"field is required"},
+				"endDate":       {"field is required"},
+				"contact.email": {"email or phone is required"},
+				"contact.phone": {"email or phone is required"},
+			},
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Validate(tt.request)
+
+			if tt.isError {
+				assert.Equal(t, apperror.Err400InvalidData, err)
+				assert.Equal(t, tt.expected, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Nil(t, result)
+			}
+		})
+	}
+}
+
+func TestStructUtil_ValidateForm_StructRules(t *testing.T) {
+	result, err := ValidateForm(ruleContact{})
+
+	assert.Equal(t, apperror.Err400InvalidData, err)
+	assert.Equal(t, map[string][]string{
+		"email_address": {"email or phone is required"},
+		"phone_number":  {"email or phone is required"},
+	}, result)
+}
+
+func TestStructUtil_ValidateCtx(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     ruleSignup
+		expected    map[string][]string
+		expectedErr error
+	}{
+		{
+			name:    "available email",
+			request: ruleSignup{Email: "free@example.com"},
+		},
+		{
+			name:        "taken email",
+			request:     ruleSignup{Email: "taken@example.com"},
+			expected:    map[string][]string{"email": {"email is already taken"}},
+			expectedErr: apperror.Err400InvalidData,
+		},
+		{
+			name:        "rule error is returned as-is",
+			request:     ruleSignup{Email: "down@example.com"},
+			expectedErr: errRuleRepository,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateCtx(context.Background(), tt.request)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	t.Run("context rules are skipped by Validate", func(t *testing.T) {
+		result, err := Validate(ruleSignup{Email: "taken@example.com"})
+
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("form paths", func(t *testing.T) {
+		result, err := ValidateFormCtx(context.Background(), ruleSignup{Email: "taken@example.com"})
+
+		assert.Equal(t, apperror.Err400InvalidData, err)
+		assert.Equal(t, map[string][]string{"email": {"email is already taken"}}, result)
+	})
+}
+
+func TestStructUtil_RegisterStructRule_NonStruct(t *testing.T) {
+	assert.Panics(t, func() {
+		RegisterStructRule(func(s string, errs *RuleErrors) {})
+	})
+}
+
+func TestStructUtil_buildFieldPath(t *testing.T) {
+	tests := []struct {
+		ns       string
+		expected string
+	}{
+		{"EndDate", "endDate"},
+		{"Contact.Email", "contact.email"},
+		{"Guests[2].Phone", "guests.2.phone"},
+		{"Unknown.Field", "Unknown.Field"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ns, func(t *testing.T) {
+			assert.Equal(t, tt.expected, buildFieldPath(reflect.TypeFor[ruleBooking](), tt.ns, getJSONTagName))
+		})
+	}
+}
+
+func BenchmarkStructutil_Validate_StructRules(b *testing.B) {
+	start := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	input := ruleBooking{
+		StartDate: start,
+		EndDate:   start.Add(-time.Hour),
+		Guests:    []ruleContact{{}, {Email: "a@example.com"}},
+	}
+
+	for b.Loop() {
+		Validate(input)
+	}
+}
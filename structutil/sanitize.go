@@ -0,0 +1,198 @@
+package structutil
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+	"golang.org/x/text/unicode/norm"
+)
+
+// SanitizerFunc cleans a single string value.
+type SanitizerFunc func(string) string
+
+var (
+	sanitizersMu sync.RWMutex
+	sanitizers   = map[string]SanitizerFunc{
+		"html":  StripHTML,
+		"space": CollapseSpace,
+		"nfc":   norm.NFC.String,
+		"nfkc":  norm.NFKC.String,
+	}
+)
+
+var (
+	htmlOpenRegex = regexp.MustCompile(`<([a-zA-Z/!?])`)
+	spaceRegex    = regexp.MustCompile(`\s+`)
+)
+
+// htmlBreaks are the tags that separate words when rendered, so StripHTML
+// replaces them with a space.
+var htmlBreaks = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true,
+	"dd": true, "details": true, "div": true, "dl": true, "dt": true,
+	"fieldset": true, "figcaption": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "main": true, "nav": true, "ol": true,
+	"p": true, "pre": true, "section": true, "summary": true, "table": true,
+	"td": true, "th": true, "tr": true, "ul": true,
+	"script": true, "style": true,
+}
+
+// RegisterSanitizer adds or replaces a sanitizer usable from the `sanitize` tag.
+//
+// Example:
+//
+//	structutil.RegisterSanitizer("digits", func(s string) string {
+//	    return strings.Map(func(r rune) rune {
+//	        if unicode.IsDigit(r) {
+//	            return r
+//	        }
+//	        return -1
+//	    }, s)
+//	})
+//
+//	type Request struct {
+//	    Phone string `json:"phone" sanitize:"digits"`
+//	}
+func RegisterSanitizer(name string, fn SanitizerFunc) {
+	sanitizersMu.Lock()
+	defer sanitizersMu.Unlock()
+
+	sanitizers[name] = fn
+}
+
+// Sanitize cleans string fields of a struct using the `sanitize` tag.
+// Supports nested structs, pointers to structs and slices of structs.
+//
+// It runs as part of BindAndValidateJSON and BindAndValidateForm, after binding
+// and before validation.
+//
+// Parameters:
+// - input: pointer to struct with `sanitize` tags.
+//
+// Returns:
+// - error: if a `sanitize` tag names an unregistered sanitizer.
+//
+// Built-in sanitizers:
+// - html: removes HTML tags, comments and the content of <script> and <style> elements.
+// - space: collapses whitespace runs into a single space and trims both ends.
+// - nfc: normalizes to Unicode NFC.
+// - nfkc: normalizes to Unicode NFKC.
+//
+// Sanitizers run in tag order on string, *string and []string fields.
+//
+// Example:
+//
+//	type CommentRequest struct {
+//	    Body string   `json:"body" sanitize:"html,space,nfc"`
+//	    Tags []string `json:"tags" sanitize:"space"`
+//	}
+//
+//	input := CommentRequest{Body: "<b>Hello</b>\n\n  <script>alert(1)</script>world"}
+//	Sanitize(&input)
+//	// input.Body: "Hello world"
+func Sanitize(input any) error {
+	v := reflect.ValueOf(input)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	return walkStruct(v.Elem(), sanitizeField)
+}
+
+// StripHTML removes HTML tags, comments and <script>/<style> blocks from s.
+// HTML entities are kept as-is so escaped markup stays escaped. Unfinished
+// tags are dropped, and a "<" left in the text that could start a tag, such
+// as one pieced together from "<<b>script>", is escaped as "&lt;".
+//
+// Block-level tags such as <p> and <div>, <br> and removed blocks leave a
+// single space between the words around them, e.g. "a<br>b" becomes "a b".
+// No space is added where whitespace already separates the words.
+func StripHTML(s string) string {
+	z := html.NewTokenizer(strings.NewReader(s))
+
+	var b strings.Builder
+	inBlock := 0
+	breakWords := false
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return htmlOpenRegex.ReplaceAllString(b.String(), "&lt;$1")
+		case html.TextToken:
+			if inBlock > 0 {
+				continue
+			}
+			text := z.Raw()
+			if breakWords && b.Len() > 0 && len(text) > 0 && !endsWithSpace(b.String()) && !isSpaceByte(text[0]) {
+				b.WriteByte(' ')
+			}
+			breakWords = false
+			b.Write(text)
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			bname, _ := z.TagName()
+			name := string(bname)
+			if htmlBreaks[name] {
+				breakWords = true
+			}
+
+			// The content of script and style is dropped along with the tags
+			if name != "script" && name != "style" || tt == html.SelfClosingTagToken {
+				continue
+			}
+			if tt == html.StartTagToken {
+				inBlock++
+			} else if inBlock > 0 {
+				inBlock--
+			}
+		}
+	}
+}
+
+func endsWithSpace(s string) bool {
+	return isSpaceByte(s[len(s)-1])
+}
+
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// CollapseSpace replaces every whitespace run in s with a single space and trims the result.
+func CollapseSpace(s string) string {
+	return strings.TrimSpace(spaceRegex.ReplaceAllString(s, " "))
+}
+
+// sanitizeField applies the `sanitize` tag of a single field.
+func sanitizeField(field reflect.StructField, fieldValue reflect.Value) error {
+	tag := field.Tag.Get("sanitize")
+	if tag == "" {
+		return nil
+	}
+
+	fns := make([]func(string) string, 0)
+	for _, name := range strings.Split(tag, ",") {
+		name = strings.TrimSpace(name)
+		fn, ok := lookupSanitizer(name)
+		if !ok {
+			return fmt.Errorf("structutil: field %s: unknown sanitizer %q", field.Name, name)
+		}
+		fns = append(fns, fn)
+	}
+
+	transformStrings(fieldValue, chainStrings(fns))
+
+	return nil
+}
+
+// lookupSanitizer returns the registered sanitizer for name.
+func lookupSanitizer(name string) (SanitizerFunc, bool) {
+	sanitizersMu.RLock()
+	defer sanitizersMu.RUnlock()
+
+	fn, ok := sanitizers[name]
+	return fn, ok
+}
@@ -0,0 +1,204 @@
+package structutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/shoraid/stx-go-utils/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructUtil_Sanitize(t *testing.T) {
+	type Comment struct {
+		Body string `json:"body" sanitize:"html,space"`
+	}
+
+	type Request struct {
+		Title    string    `json:"title" sanitize:"html,space,nfc"`
+		Subtitle *string   `json:"subtitle" sanitize:"space"`
+		Tags     []string  `json:"tags" sanitize:"html"`
+		Raw      string    `json:"raw"`
+		Comments []Comment `json:"comments"`
+		Pinned   *Comment  `json:"pinned"`
+	}
+
+	subtitle := "  a \t sub\ntitle "
+
+	input := Request{
+		Title:    "<b>Cafe\u0301</b>\n\n  <script>alert(1)</script>menu",
+		Subtitle: &subtitle,
+		Tags:     []string{"<i>go</i>", "api"},
+		Raw:      "<b>kept</b>",
+		Comments: []Comment{{Body: "<p>hello   <!-- hidden -->world</p>"}},
+		Pinned:   &Comment{Body: "<style>p{}</style><a href=\"#\">link</a>"},
+	}
+
+	err := Sanitize(&input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Café menu", input.Title)
+	assert.Equal(t, "a sub title", *input.Subtitle)
+	assert.Equal(t, []string{"go", "api"}, input.Tags)
+	assert.Equal(t, "<b>kept</b>", input.Raw)
+	assert.Equal(t, "hello world", input.Comments[0].Body)
+	assert.Equal(t, "link", input.Pinned.Body)
+}
+
+func TestStructUtil_Sanitize_UnknownSanitizer(t *testing.T) {
+	var input struct {
+		Name string `sanitize:"html,unknown"`
+	}
+
+	err := Sanitize(&input)
+	assert.ErrorContains(t, err, `unknown sanitizer "unknown"`)
+}
+
+func TestStructUtil_RegisterSanitizer(t *testing.T) {
+	RegisterSanitizer("digits", func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsDigit(r) {
+				return r
+			}
+			return -1
+		}, s)
+	})
+
+	var input struct {
+		Phone string `sanitize:"digits"`
+	}
+	input.Phone = "+62 (812) 345-678"
+
+	err := Sanitize(&input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "62812345678", input.Phone)
+}
+
+func TestStructUtil_StripHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain text", "hello world", "hello world"},
+		{"simple tags", "<b>bold</b> and <i>italic</i>", "bold and italic"},
+		{"script block", "a<script type=\"text/javascript\">alert('x')</script>b", "a b"},
+		{"uppercase script block", "a<SCRIPT>alert(1)</SCRIPT>b", "a b"},
+		{"comment", "a<!-- secret -->b", "ab"},
+		{"escaped markup kept", "&lt;script&gt;", "&lt;script&gt;"},
+		{"comparison kept", "1 < 2 and 3 > 2", "1 < 2 and 3 > 2"},
+		{"nested tags", "<<b>script>alert(1)<</b>/script>", "&lt;script>alert(1)&lt;/script>"},
+		{"unclosed tag", "<img src=x onerror=alert(1) ", ""},
+		{"unclosed tag after text", "hello <img src=x onerror=alert(1) ", "hello "},
+		{"unclosed script block", "a<script>alert(1)", "a"},
+		{"line break", "a<br>b<br/>c", "a b c"},
+		{"paragraphs", "<p>x</p><p>y</p>", "x y"},
+		{"block tags next to whitespace", "<div>x</div>\n<div> y</div>", "x\n y"},
+		{"inline tags join", "un<b>break</b>able", "unbreakable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, StripHTML(tt.input))
+		})
+	}
+}
+
+func TestStructUtil_CollapseSpace(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hello world", "hello world"},
+		{"  hello \t\n world  ", "hello world"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CollapseSpace(tt.input))
+		})
+	}
+}
+
+func TestStructUtil_BindAndValidateJSON_Sanitize(t *testing.T) {
+	type Request struct {
+		Body string `json:"body" validate:"required,max=20" sanitize:"html,space"`
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		expected       string
+		expectedError  error
+		expectedFields map[string][]string
+	}{
+		{
+			name:     "sanitized before validation",
+			body:     `{"body":"<p>hello</p>  <script>alert(1)</script>"}`,
+			expected: "hello",
+		},
+		{
+			name:          "markup only becomes empty",
+			body:          `{"body":"<script>alert(1)</script>"}`,
+			expectedError: apperror.Err400InvalidData,
+			expectedFields: map[string][]string{
+				"body": {"field is required"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/comments", strings.NewReader(tt.body))
+
+			var input Request
+			result, err := BindAndValidateJSON(req, &input)
+
+			assert.Equal(t, tt.expected, input.Body)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Equal(t, tt.expectedFields, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Nil(t, result)
+			}
+		})
+	}
+}
+
+func TestStructUtil_BindAndValidateForm_Sanitize(t *testing.T) {
+	type Request struct {
+		Body string `form:"body" validate:"required" sanitize:"html,space"`
+	}
+
+	formData := url.Values{"body": {"<em>hi</em>\n\nthere"}}
+	req := httptest.NewRequest(http.MethodPost, "/comments", strings.NewReader(formData.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var input Request
+	result, err := BindAndValidateForm(req, &input)
+
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "hi there", input.Body)
+}
+
+func BenchmarkStructutil_Sanitize(b *testing.B) {
+	type Request struct {
+		Title string `json:"title" sanitize:"html,space,nfc"`
+		Body  string `json:"body" sanitize:"html"`
+	}
+
+	for b.Loop() {
+		input := Request{
+			Title: "<b>Hello</b>   world",
+			Body:  "<p>Lorem <script>alert(1)</script>ipsum</p>",
+		}
+		Sanitize(&input)
+	}
+}
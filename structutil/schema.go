@@ -0,0 +1,477 @@
+package structutil
+
+import (
+	"mime/multipart"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JSONSchemaDraft is the dialect declared by GenerateJSONSchema.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document. The same type is used for OpenAPI 3.1
+// component schemas, which are JSON Schema compatible.
+type Schema struct {
+	SchemaURI            string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// formatRules maps string `validate` rules to JSON Schema formats.
+var formatRules = map[string]string{
+	"email":    "email",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"uuid7":    "uuid",
+	"url":      "uri",
+	"uri":      "uri",
+	"hostname": "hostname",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+}
+
+// patternRules maps string `validate` rules to JSON Schema patterns.
+var patternRules = map[string]string{
+	"alpha":    "^[a-zA-Z]+$",
+	"alphanum": "^[a-zA-Z0-9]+$",
+	"numeric":  "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+	"e164":     "^\\+[1-9]?[0-9]{7,14}$",
+}
+
+var (
+	// typeArgRegex matches a package qualified type argument of a generic
+	// instantiation, e.g. "github.com/x/y.User" in "Page[github.com/x/y.User]"
+	typeArgRegex = regexp.MustCompile(`[\w.\-/]+\.(\w+)`)
+	// defNameRegex matches runs of characters not allowed in component keys
+	defNameRegex = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+	// oneofValueRegex matches a value of a oneof rule, which validator lets
+	// single-quote to include spaces, e.g. oneof='pro plus' free
+	oneofValueRegex = regexp.MustCompile(`'[^']*'|\S+`)
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	uuidType       = reflect.TypeOf(uuid.UUID{})
+	fileHeaderType = reflect.TypeOf(multipart.FileHeader{})
+)
+
+// GenerateJSONSchema generates a JSON Schema from a struct using its `json`,
+// `validate` and `default` tags, so the documented contract matches Validate.
+//
+// Parameters:
+// - input: struct or pointer to struct.
+//
+// Returns:
+// - *Schema: the root schema. Nested named structs are placed in `$defs` and
+// referenced with `$ref`, which also supports recursive types. See
+// GenerateOpenAPISchemas for how definitions are named.
+//
+// Supported validate rules:
+// - required: adds the field to `required`.
+// - min, max, len, gt, gte, lt, lte: length for strings, bounds for numbers,
+// item count for slices and property count for maps.
+// - oneof: `enum`.
+// - email, uuid, url, uri, hostname, ipv4, ipv6: `format`.
+// - alpha, alphanum, numeric, e164: `pattern`.
+// - dive: the following rules apply to slice items or map values.
+//
+// Example:
+//
+//	type UserRequest struct {
+//	    Name  string   `json:"name" validate:"required,max=100"`
+//	    Role  string   `json:"role" validate:"oneof=admin user" default:"user"`
+//	    Tags  []string `json:"tags" validate:"max=5,dive,min=2"`
+//	}
+//
+//	GenerateJSONSchema(UserRequest{})
+//	// Output (as JSON):
+//	{
+//	    "$schema": "https://json-schema.org/draft/2020-12/schema",
+//	    "type": "object",
+//	    "properties": {
+//	        "name": {"type": "string", "maxLength": 100},
+//	        "role": {"type": "string", "enum": ["admin", "user"], "default": "user"},
+//	        "tags": {"type": "array", "items": {"type": "string", "minLength": 2}, "maxItems": 5}
+//	    },
+//	    "required": ["name"]
+//	}
+func GenerateJSONSchema(input any) *Schema {
+	root := derefType(reflect.TypeOf(input))
+
+	g := &schemaGenerator{
+		refPrefix: "#/$defs/",
+		root:      root,
+		defs:      make(map[string]*Schema),
+		names:     make(map[reflect.Type]string),
+	}
+
+	schema := g.structSchema(root)
+	schema.SchemaURI = JSONSchemaDraft
+	if len(g.defs) > 0 {
+		schema.Defs = g.defs
+	}
+
+	return schema
+}
+
+// GenerateOpenAPISchemas generates OpenAPI component schemas for the given structs
+// and every named struct they reference, keyed by Go type name. References use
+// "#/components/schemas/<Name>", so the result can be placed under
+// `components.schemas` as-is.
+//
+// Generic instantiations drop the package paths of their type arguments and
+// join them with "_", e.g. Page[github.com/x/y.User] becomes "Page_User". The
+// first struct seen keeps its plain name. Any other struct with the same name,
+// whether from another package or declared in a function of the same package,
+// is prefixed with its package name, e.g. "billing.User", and numbered if that
+// is taken too, e.g. "User_2".
+//
+// Example:
+//
+//	schemas := GenerateOpenAPISchemas(CreateUserRequest{}, UpdateUserRequest{})
+//	// schemas["CreateUserRequest"], schemas["UpdateUserRequest"], schemas["Role"], ...
+func GenerateOpenAPISchemas(inputs ...any) map[string]*Schema {
+	g := &schemaGenerator{
+		refPrefix: "#/components/schemas/",
+		defs:      make(map[string]*Schema),
+		names:     make(map[reflect.Type]string),
+	}
+
+	for _, input := range inputs {
+		g.refSchema(derefType(reflect.TypeOf(input)))
+	}
+
+	return g.defs
+}
+
+// schemaGenerator builds schemas and collects named struct definitions.
+type schemaGenerator struct {
+	refPrefix string
+	root      reflect.Type
+	defs      map[string]*Schema
+	names     map[reflect.Type]string
+}
+
+// typeSchema returns the schema of t without any validation rules applied.
+func (g *schemaGenerator) typeSchema(t reflect.Type) *Schema {
+	t = derefType(t)
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case fileHeaderType:
+		return &Schema{Type: "string", Format: "binary"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16:
+		return &Schema{Type: "integer"}
+
+	case reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0.0
+		return &Schema{Type: "integer", Minimum: &zero}
+
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.typeSchema(t.Elem())}
+
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.typeSchema(t.Elem())}
+
+	case reflect.Struct:
+		return g.refSchema(t)
+	}
+
+	// interface{} and other kinds accept any value
+	return &Schema{}
+}
+
+// refSchema returns a reference to a named struct, generating its definition
+// on first use. Anonymous structs are inlined.
+func (g *schemaGenerator) refSchema(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return g.structSchema(t)
+	}
+
+	if t == g.root {
+		return &Schema{Ref: "#"}
+	}
+
+	name, ok := g.names[t]
+	if !ok {
+		name = g.defName(t)
+
+		// Reserve the name first so recursive types terminate
+		g.names[t] = name
+		g.defs[name] = &Schema{}
+		*g.defs[name] = *g.structSchema(t)
+	}
+
+	return &Schema{Ref: g.refPrefix + name}
+}
+
+// defName returns an unused definition key for t, made of characters valid in
+// a component key and a JSON pointer.
+func (g *schemaGenerator) defName(t reflect.Type) string {
+	name := t.Name()
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i] + typeArgRegex.ReplaceAllString(name[i:], "$1")
+	}
+	name = strings.Trim(defNameRegex.ReplaceAllString(name, "_"), "_")
+
+	if _, taken := g.defs[name]; !taken {
+		return name
+	}
+
+	if pkg := path.Base(t.PkgPath()); pkg != "." && pkg != "/" {
+		qualified := defNameRegex.ReplaceAllString(pkg, "_") + "." + name
+		if _, taken := g.defs[qualified]; !taken {
+			return qualified
+		}
+	}
+
+	for i := 2; ; i++ {
+		numbered := name + "_" + strconv.Itoa(i)
+		if _, taken := g.defs[numbered]; !taken {
+			return numbered
+		}
+	}
+}
+
+// structSchema builds an object schema from the exported fields of t.
+func (g *schemaGenerator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addStructFields(schema, t)
+
+	return schema
+}
+
+func (g *schemaGenerator) addStructFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonTag := field.Tag.Get("json")
+
+		if jsonTag == "-" {
+			continue
+		}
+
+		// Embedded structs without a json name are flattened, like encoding/json does
+		if field.Anonymous && strings.Split(jsonTag, ",")[0] == "" && derefType(field.Type).Kind() == reflect.Struct {
+			g.addStructFields(schema, derefType(field.Type))
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name := getJSONTagName(field)
+		fieldSchema := g.typeSchema(field.Type)
+
+		if rules := field.Tag.Get("validate"); rules != "" {
+			var required bool
+			fieldSchema, required = g.applyRules(fieldSchema, field.Type, rules)
+			if required {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+
+		if def, ok := field.Tag.Lookup("default"); ok {
+			fieldSchema.Default = parseSchemaDefault(field.Type, def)
+		}
+
+		schema.Properties[name] = fieldSchema
+	}
+}
+
+// applyRules translates `validate` rules into schema keywords and reports whether
+// the field is required. Rules after `dive` are applied to items or map values.
+// Keywords next to $ref are valid in JSON Schema 2020-12 and OpenAPI 3.1.
+func (g *schemaGenerator) applyRules(schema *Schema, t reflect.Type, rules string) (*Schema, bool) {
+	t = derefType(t)
+	required := false
+
+	parts := strings.Split(rules, ",")
+	for i := 0; i < len(parts); i++ {
+		rule := strings.TrimSpace(parts[i])
+		name, param, _ := strings.Cut(rule, "=")
+
+		// Alternatives (e.g. "email|e164") cannot be expressed with a single keyword
+		if strings.Contains(rule, "|") {
+			continue
+		}
+
+		switch name {
+		case "required":
+			required = true
+
+		case "dive":
+			rest := strings.Join(parts[i+1:], ",")
+			switch {
+			case schema.Items != nil:
+				schema.Items, _ = g.applyRules(schema.Items, t.Elem(), rest)
+			case schema.AdditionalProperties != nil:
+				schema.AdditionalProperties, _ = g.applyRules(schema.AdditionalProperties, t.Elem(), rest)
+			}
+			return schema, required
+
+		case "min", "max", "len", "gt", "gte", "lt", "lte":
+			applyBound(schema, t, name, param)
+
+		case "oneof":
+			for _, v := range oneofValueRegex.FindAllString(param, -1) {
+				schema.Enum = append(schema.Enum, parseSchemaValue(t, strings.ReplaceAll(v, "'", "")))
+			}
+
+		default:
+			if format, ok := formatRules[name]; ok {
+				schema.Format = format
+			} else if pattern, ok := patternRules[name]; ok {
+				schema.Pattern = pattern
+			}
+		}
+	}
+
+	return schema, required
+}
+
+// applyBound sets the length, range, item or property keyword matching the kind of t.
+func applyBound(schema *Schema, t reflect.Type, rule, param string) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		switch rule {
+		case "min", "gte":
+			schema.Minimum = &n
+		case "max", "lte":
+			schema.Maximum = &n
+		case "len":
+			schema.Minimum, schema.Maximum = &n, &n
+		case "gt":
+			schema.ExclusiveMinimum = &n
+		case "lt":
+			schema.ExclusiveMaximum = &n
+		}
+		return
+	}
+
+	count := int(n)
+	var minCount, maxCount **int
+
+	switch t.Kind() {
+	case reflect.String:
+		minCount, maxCount = &schema.MinLength, &schema.MaxLength
+	case reflect.Slice, reflect.Array:
+		minCount, maxCount = &schema.MinItems, &schema.MaxItems
+	case reflect.Map:
+		minCount, maxCount = &schema.MinProperties, &schema.MaxProperties
+	default:
+		return
+	}
+
+	switch rule {
+	case "min", "gte":
+		*minCount = &count
+	case "max", "lte":
+		*maxCount = &count
+	case "len":
+		*minCount, *maxCount = &count, &count
+	case "gt":
+		count++
+		*minCount = &count
+	case "lt":
+		count--
+		*maxCount = &count
+	}
+}
+
+// parseSchemaDefault converts a `default` tag value to the JSON type of t.
+func parseSchemaDefault(t reflect.Type, def string) any {
+	t = derefType(t)
+	if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		values := make([]any, 0)
+		for _, v := range strings.Split(def, ",") {
+			values = append(values, parseSchemaValue(t.Elem(), v))
+		}
+		return values
+	}
+
+	return parseSchemaValue(t, def)
+}
+
+// parseSchemaValue converts a tag value to the JSON type of t, falling back to the raw string.
+func parseSchemaValue(t reflect.Type, value string) any {
+	switch derefType(t).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+
+	return value
+}
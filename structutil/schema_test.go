@@ -0,0 +1,221 @@
+package structutil
+
+import (
+	"encoding/json"
+	"image"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type schemaRole struct {
+	ID   string `json:"id" validate:"required,uuid"`
+	Name string `json:"name" validate:"required,min=3,max=50"`
+}
+
+type globalSchemaRole = schemaRole
+
+type schemaCategory struct {
+	Name   string            `json:"name" validate:"required"`
+	Parent *schemaCategory   `json:"parent"`
+	Labels map[string]string `json:"labels" validate:"max=3,dive,max=10"`
+}
+
+type schemaAudit struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+type schemaUserRequest struct {
+	schemaAudit
+	Name      string                `json:"name" validate:"required,max=100"`
+	Email     *string               `json:"email" validate:"omitempty,email"`
+	Age       int                   `json:"age" validate:"gte=18,lt=130"`
+	Score     float64               `json:"score" validate:"gt=0"`
+	Status    string                `json:"status" validate:"oneof=active inactive" default:"active"`
+	Level     int                   `json:"level" validate:"oneof=1 2 3"`
+	Plan      string                `json:"plan" validate:"oneof='pro plus' free"`
+	Code      string                `json:"code" validate:"len=6,alphanum"`
+	Contact   string                `json:"contact" validate:"email|e164"`
+	Roles     []schemaRole          `json:"roles" validate:"required,min=1,dive"`
+	TagIDs    []string              `json:"tagIds" validate:"dive,uuid"`
+	Sort      []string              `json:"sort" default:"name,createdAt"`
+	Category  schemaCategory        `json:"category"`
+	BirthDate time.Time             `json:"birthDate"`
+	TenantID  uuid.UUID             `json:"tenantId"`
+	Avatar    *multipart.FileHeader `json:"avatar"`
+	Raw       []byte                `json:"raw"`
+	Extra     any                   `json:"extra"`
+	Count     uint                  `json:"count"`
+	Ignored   string                `json:"-"`
+	internal  string
+}
+
+func TestStructUtil_GenerateJSONSchema(t *testing.T) {
+	schema := GenerateJSONSchema(&schemaUserRequest{})
+
+	intPtr := func(n int) *int { return &n }
+	floatPtr := func(n float64) *float64 { return &n }
+
+	assert.Equal(t, JSONSchemaDraft, schema.SchemaURI)
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"name", "roles"}, schema.Required)
+
+	tests := []struct {
+		property string
+		expected *Schema
+	}{
+		{"createdBy", &Schema{Type: "string"}},
+		{"name", &Schema{Type: "string", MaxLength: intPtr(100)}},
+		{"email", &Schema{Type: "string", Format: "email"}},
+		{"age", &Schema{Type: "integer", Minimum: floatPtr(18), ExclusiveMaximum: floatPtr(130)}},
+		{"score", &Schema{Type: "number", Format: "double", ExclusiveMinimum: floatPtr(0)}},
+		{"status", &Schema{Type: "string", Enum: []any{"active", "inactive"}, Default: "active"}},
+		{"level", &Schema{Type: "integer", Enum: []any{int64(1), int64(2), int64(3)}}},
+		{"plan", &Schema{Type: "string", Enum: []any{"pro plus", "free"}}},
+		{"code", &Schema{Type: "string", MinLength: intPtr(6), MaxLength: intPtr(6), Pattern: "^[a-zA-Z0-9]+$"}},
+		{"contact", &Schema{Type: "string"}},
+		{"roles", &Schema{Type: "array", MinItems: intPtr(1), Items: &Schema{Ref: "#/$defs/schemaRole"}}},
+		{"tagIds", &Schema{Type: "array", Items: &Schema{Type: "string", Format: "uuid"}}},
+		{"sort", &Schema{Type: "array", Items: &Schema{Type: "string"}, Default: []any{"name", "createdAt"}}},
+		{"category", &Schema{Ref: "#/$defs/schemaCategory"}},
+		{"birthDate", &Schema{Type: "string", Format: "date-time"}},
+		{"tenantId", &Schema{Type: "string", Format: "uuid"}},
+		{"avatar", &Schema{Type: "string", Format: "binary"}},
+		{"raw", &Schema{Type: "string", Format: "byte"}},
+		{"extra", &Schema{}},
+		{"count", &Schema{Type: "integer", Minimum: floatPtr(0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.property, func(t *testing.T) {
+			assert.Equal(t, tt.expected, schema.Properties[tt.property])
+		})
+	}
+
+	t.Run("skipped fields", func(t *testing.T) {
+		assert.Len(t, schema.Properties, len(tests))
+		assert.NotContains(t, schema.Properties, "Ignored")
+		assert.NotContains(t, schema.Properties, "internal")
+	})
+
+	t.Run("definitions", func(t *testing.T) {
+		assert.Equal(t, &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"id":   {Type: "string", Format: "uuid"},
+				"name": {Type: "string", MinLength: intPtr(3), MaxLength: intPtr(50)},
+			},
+			Required: []string{"id", "name"},
+		}, schema.Defs["schemaRole"])
+
+		assert.Equal(t, &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"name":   {Type: "string"},
+				"parent": {Ref: "#/$defs/schemaCategory"},
+				"labels": {Type: "object", MaxProperties: intPtr(3), AdditionalProperties: &Schema{Type: "string", MaxLength: intPtr(10)}},
+			},
+			Required: []string{"name"},
+		}, schema.Defs["schemaCategory"])
+	})
+}
+
+func TestStructUtil_GenerateJSONSchema_RecursiveRoot(t *testing.T) {
+	schema := GenerateJSONSchema(schemaCategory{})
+
+	assert.Equal(t, &Schema{Ref: "#"}, schema.Properties["parent"])
+	assert.Nil(t, schema.Defs)
+}
+
+func TestStructUtil_GenerateJSONSchema_JSON(t *testing.T) {
+	schema := GenerateJSONSchema(schemaRole{})
+
+	data, err := json.Marshal(schema)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"name": {"type": "string", "minLength": 3, "maxLength": 50}
+		},
+		"required": ["id", "name"]
+	}`, string(data))
+}
+
+func TestStructUtil_GenerateOpenAPISchemas(t *testing.T) {
+	schemas := GenerateOpenAPISchemas(schemaUserRequest{}, &schemaRole{})
+
+	assert.ElementsMatch(t, []string{"schemaUserRequest", "schemaRole", "schemaCategory"}, keysOf(schemas))
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/schemaRole"}, schemas["schemaUserRequest"].Properties["roles"].Items)
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/schemaCategory"}, schemas["schemaCategory"].Properties["parent"])
+	assert.Empty(t, schemas["schemaUserRequest"].SchemaURI)
+}
+
+type schemaPage[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+func TestStructUtil_GenerateOpenAPISchemas_Names(t *testing.T) {
+	type Point struct {
+		Label string `json:"label"`
+	}
+	type shape struct {
+		Center Point       `json:"center"`
+		Corner image.Point `json:"corner"`
+	}
+
+	t.Run("same name from another package", func(t *testing.T) {
+		schemas := GenerateOpenAPISchemas(shape{})
+
+		assert.ElementsMatch(t, []string{"shape", "Point", "image.Point"}, keysOf(schemas))
+		assert.Equal(t, &Schema{Ref: "#/components/schemas/Point"}, schemas["shape"].Properties["center"])
+		assert.Equal(t, &Schema{Ref: "#/components/schemas/image.Point"}, schemas["shape"].Properties["corner"])
+		assert.Contains(t, schemas["image.Point"].Properties, "X")
+	})
+
+	t.Run("same name from the same package", func(t *testing.T) {
+		type schemaRole struct {
+			Slug string `json:"slug"`
+		}
+		shadow := func() any {
+			type schemaRole struct {
+				Key string `json:"key"`
+			}
+			return schemaRole{}
+		}
+
+		schemas := GenerateOpenAPISchemas(&schemaRole{}, globalSchemaRole{}, shadow())
+
+		assert.ElementsMatch(t, []string{"schemaRole", "structutil.schemaRole", "schemaRole_2"}, keysOf(schemas))
+		assert.Contains(t, schemas["schemaRole"].Properties, "slug")
+		assert.Contains(t, schemas["structutil.schemaRole"].Properties, "id")
+		assert.Contains(t, schemas["schemaRole_2"].Properties, "key")
+	})
+
+	t.Run("generic instantiations", func(t *testing.T) {
+		schemas := GenerateOpenAPISchemas(schemaPage[schemaRole]{}, schemaPage[map[string]*image.Point]{})
+
+		assert.ElementsMatch(t, []string{"schemaPage_schemaRole", "schemaRole", "schemaPage_map_string_Point", "Point"}, keysOf(schemas))
+		assert.Equal(t, &Schema{Ref: "#/components/schemas/schemaRole"}, schemas["schemaPage_schemaRole"].Properties["items"].Items)
+	})
+}
+
+func keysOf(m map[string]*Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func BenchmarkStructutil_GenerateJSONSchema(b *testing.B) {
+	for b.Loop() {
+		GenerateJSONSchema(schemaUserRequest{})
+	}
+}
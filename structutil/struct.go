@@ -7,6 +7,25 @@ import (
 	"github.com/shoraid/stx-go-utils/apperror"
 )
 
+// BindJSON decodes a JSON request body into input. Unknown fields are rejected.
+//
+// Parameters:
+// - r: HTTP request with a JSON body.
+// - input: pointer to struct with `json` tags.
+//
+// Returns:
+// - error: apperror.Err400InvalidBody if there is no body, or the decoding error.
+//
+// `default` and `mod` tags are applied after decoding, see Normalize. Like
+// Normalize, BindJSON panics on a struct type with a `default` tag on a bool or
+// numeric field that is not a pointer, e.g. `PerPage int default:"20"`; use *int.
+//
+// Example:
+//
+//	var input CreateUserRequest
+//	if err := BindJSON(r, &input); err != nil {
+//	    return err
+//	}
 func BindJSON(r *http.Request, input any) error {
 	if r.Body == nil {
 		return apperror.Err400InvalidBody
@@ -18,5 +37,5 @@ func BindJSON(r *http.Request, input any) error {
 		return err
 	}
 
-	return nil
+	return Normalize(input)
 }